@@ -1,44 +1,11 @@
 package main
 
 import (
-	"context"
-	"flag"
-	"fmt"
-
-	"github.com/Insulince/jeth/pkg/convert"
-	"github.com/Insulince/jeth/pkg/eth"
-
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/pkg/errors"
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/checkbalance"
 )
 
+// main runs check-balance on its own, it is the same as "jeth check-balance".
 func main() {
-	ctx := context.Background()
-
-	var address string
-
-	flag.StringVar(&address, "address", "", "the wallet address whose balance you wish to check")
-	flag.Parse()
-
-	if address == "" {
-		panic(errors.New("address cannot be blank, please provide a valid address via -address"))
-	}
-
-	client, err := ethclient.Dial(eth.DefaultGateway)
-	if err != nil {
-		panic(errors.Wrap(err, "dialing eth gateway"))
-	}
-
-	account := common.HexToAddress(address)
-	balance, err := client.BalanceAt(ctx, account, eth.LatestBlock)
-	if err != nil {
-		panic(errors.Wrap(err, "fetching account balance"))
-	}
-
-	eth := convert.WeiIToEth(balance)
-	fmt.Println(balance)
-	fmt.Println(eth.String())
-	usd := convert.EthToUsd(eth, 3195)
-	fmt.Println(usd.String())
+	cli.MainCommand(checkbalance.Command)
 }
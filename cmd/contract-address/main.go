@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/contractaddress"
+)
+
+// main runs contract-address on its own, it is the same as "jeth contract-address".
+func main() {
+	cli.MainCommand(contractaddress.Command)
+}
@@ -1,24 +1,11 @@
 package main
 
 import (
-	"context"
-	"fmt"
-
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/pkg/errors"
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/gas"
 )
 
+// main runs gas on its own, it is the same as "jeth gas".
 func main() {
-	ctx := context.Background()
-
-	client, err := ethclient.Dial("https://cloudflare-eth.com")
-	if err != nil {
-		panic(errors.Wrap(err, "dialing eth gateway"))
-	}
-
-	bGasPrice, err := client.SuggestGasPrice(ctx)
-	if err != nil {
-		panic(errors.Wrap(err, "getting suggested gas price"))
-	}
-	fmt.Println(bGasPrice.String())
+	cli.MainCommand(gas.Command)
 }
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/importcmd"
+)
+
+// main runs import on its own, it is the same as "jeth import".
+func main() {
+	cli.MainCommand(importcmd.Command)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/checkbalance"
+	"github.com/Insulince/jeth/pkg/cli/contractaddress"
+	"github.com/Insulince/jeth/pkg/cli/gas"
+	"github.com/Insulince/jeth/pkg/cli/importcmd"
+	"github.com/Insulince/jeth/pkg/cli/newwallet"
+	"github.com/Insulince/jeth/pkg/cli/recoverkey"
+	"github.com/Insulince/jeth/pkg/cli/recoversigner"
+	"github.com/Insulince/jeth/pkg/cli/send"
+	"github.com/Insulince/jeth/pkg/cli/signmessage"
+	"github.com/Insulince/jeth/pkg/cli/signtypeddata"
+	"github.com/Insulince/jeth/pkg/cli/splitkey"
+	"github.com/Insulince/jeth/pkg/cli/storecmd"
+	"github.com/Insulince/jeth/pkg/cli/sweep"
+	"github.com/Insulince/jeth/pkg/cli/verifymessage"
+)
+
+func main() {
+	cli.Main([]*cli.Command{
+		checkbalance.Command,
+		contractaddress.Command,
+		gas.Command,
+		importcmd.Command,
+		newwallet.Command,
+		recoverkey.Command,
+		recoversigner.Command,
+		send.Command,
+		send.NFTCommand,
+		signmessage.Command,
+		signtypeddata.Command,
+		splitkey.Command,
+		storecmd.Command,
+		sweep.Command,
+		verifymessage.Command,
+	})
+}
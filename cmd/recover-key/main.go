@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/recoverkey"
+)
+
+// main runs recover-key on its own, it is the same as "jeth recover-key".
+func main() {
+	cli.MainCommand(recoverkey.Command)
+}
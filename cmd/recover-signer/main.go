@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/recoversigner"
+)
+
+// main runs recover-signer on its own, it is the same as "jeth recover-signer".
+func main() {
+	cli.MainCommand(recoversigner.Command)
+}
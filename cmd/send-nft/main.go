@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/send"
+)
+
+// main runs send-nft on its own, it is the same as "jeth send-nft".
+func main() {
+	cli.MainCommand(send.NFTCommand)
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/signmessage"
+)
+
+// main runs sign-message on its own, it is the same as "jeth sign-message".
+func main() {
+	cli.MainCommand(signmessage.Command)
+}
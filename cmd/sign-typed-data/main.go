@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/signtypeddata"
+)
+
+// main runs sign-typed-data on its own, it is the same as "jeth sign-typed-data".
+func main() {
+	cli.MainCommand(signtypeddata.Command)
+}
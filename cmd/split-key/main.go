@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/splitkey"
+)
+
+// main runs split-key on its own, it is the same as "jeth split-key".
+func main() {
+	cli.MainCommand(splitkey.Command)
+}
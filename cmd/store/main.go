@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/storecmd"
+)
+
+// main runs store on its own, it is the same as "jeth store".
+func main() {
+	cli.MainCommand(storecmd.Command)
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/sweep"
+)
+
+// main runs sweep on its own, it is the same as "jeth sweep".
+func main() {
+	cli.MainCommand(sweep.Command)
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/cli/verifymessage"
+)
+
+// main runs verify-message on its own, it is the same as "jeth verify-message".
+func main() {
+	cli.MainCommand(verifymessage.Command)
+}
@@ -0,0 +1,36 @@
+// Package abi calls contract functions through go-ethereum's ABI encoding, for the packages wrapping a contract standard such as pkg/erc20 and pkg/nft.
+package abi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	ethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// Call calls method of contract, as described by parsed, with args against the latest state, returning its undecoded output.
+// An account without code returns nothing for every call rather than failing, so callers should treat an empty output as such.
+func Call(ctx context.Context, caller ethereum.ContractCaller, parsed ethabi.ABI, contract common.Address, method string, args ...interface{}) ([]byte, error) {
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "packing %s", method)
+	}
+
+	out, err := caller.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "calling %s of %s", method, contract.Hex())
+	}
+	return out, nil
+}
+
+// MustParse parses the JSON ABI s, panicking if it is invalid. It is meant for package level ABIs, where that can only be a programming error.
+func MustParse(s string) ethabi.ABI {
+	parsed, err := ethabi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(errors.Wrap(err, "parsing abi"))
+	}
+	return parsed
+}
@@ -0,0 +1,64 @@
+package abi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Insulince/jeth/pkg/abi/abitest"
+)
+
+var (
+	contract = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+	parsed = MustParse(`[
+		{"type": "function", "name": "decimals", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "uint8"}]}
+	]`)
+)
+
+func Test_Call(t *testing.T) {
+	tests := []struct {
+		name     string
+		caller   abitest.FakeCaller
+		method   string
+		expected []byte
+		err      bool
+	}{
+		{
+			name:     "output",
+			caller:   abitest.FakeCaller{"0x313ce567": abitest.Word("12")},
+			method:   "decimals",
+			expected: abitest.Word("12"),
+		},
+		{
+			name:   "no code",
+			caller: abitest.FakeCaller{},
+			method: "decimals",
+		},
+		{
+			name:   "unknown method",
+			caller: abitest.FakeCaller{},
+			method: "symbol",
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, err := Call(context.Background(), test.caller, parsed, contract, test.method)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, out)
+		})
+	}
+}
+
+func Test_MustParse(t *testing.T) {
+	assert.Panics(t, func() { MustParse(`[{"type": "function", "name": `) })
+}
@@ -0,0 +1,28 @@
+// Package abitest provides a fake ethereum.ContractCaller for testing the packages built on pkg/abi.
+package abitest
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// FakeCaller answers calls by their calldata, with the output registered for either the whole calldata or its 4 byte selector, as hexutil encodes them.
+// Anything unregistered returns nothing, as an account without code would.
+type FakeCaller map[string][]byte
+
+// CallContract implements ethereum.ContractCaller.
+func (f FakeCaller) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	if out, ok := f[hexutil.Encode(call.Data)]; ok {
+		return out, nil
+	}
+	return f[hexutil.Encode(call.Data[:4])], nil
+}
+
+// Word returns s, which is hexadecimal without "0x", left padded to a 32 byte ABI word.
+func Word(s string) []byte {
+	return common.LeftPadBytes(common.FromHex(s), 32)
+}
@@ -0,0 +1,217 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/pkg/errors"
+)
+
+// Record is a generated wallet as it is written out by WriteCSV and WriteJSONL.
+type Record struct {
+	// Index is the wallet's position in the batch, starting at 0, it matches the wallet's line in the output of WriteKeystores.
+	Index     int    `json:"index"`
+	Address   string `json:"address"`
+	PublicKey string `json:"publicKey"`
+	// PrivateKey is only set when private keys are written alongside the public data, see NewRecords.
+	PrivateKey string `json:"privateKey,omitempty"`
+}
+
+// NewRecords returns a Record for each of wallets, in order. Private keys are only included if includePrivateKeys is set, otherwise the records are safe to share.
+func NewRecords(wallets []*wallet.Wallet, includePrivateKeys bool) []Record {
+	records := make([]Record, len(wallets))
+	for i, w := range wallets {
+		records[i] = Record{
+			Index:     i,
+			Address:   w.Address(),
+			PublicKey: w.PublicKeyHex(),
+		}
+		if includePrivateKeys {
+			records[i].PrivateKey = w.PrivateKeyHex()
+		}
+	}
+	return records
+}
+
+// Generate generates count random wallets across workers goroutines, validating each one.
+// generated, if not nil, is atomically incremented for every wallet generated so that the caller can report progress while it runs.
+// If ctx is cancelled before every wallet is generated, ctx.Err() is returned.
+func Generate(ctx context.Context, count, workers int, generated *uint64) ([]*wallet.Wallet, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("must generate at least 1 wallet, got %v", count)
+	}
+
+	wallets := make([]*wallet.Wallet, count)
+	err := parallel(ctx, count, workers, func(i int) error {
+		w, err := wallet.New()
+		if err != nil {
+			return errors.Wrap(err, "generating wallet")
+		}
+		if err := w.Validate(); err != nil {
+			return errors.Wrap(err, "generated wallet is invalid")
+		}
+		wallets[i] = w
+		if generated != nil {
+			atomic.AddUint64(generated, 1)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallets, nil
+}
+
+// EncryptKeystores encrypts each of wallets into a keystore with passphrase and opts across workers goroutines, returning them in the same order.
+// Each keystore is decrypted again to be certain its wallet can actually be recovered from it.
+// Encrypting is slow by design, so encrypted, if not nil, is atomically incremented for every keystore finished so that the caller can report progress while it runs.
+func EncryptKeystores(ctx context.Context, wallets []*wallet.Wallet, passphrase string, opts wallet.KeystoreOptions, workers int, encrypted *uint64) ([][]byte, error) {
+	keystores := make([][]byte, len(wallets))
+	err := parallel(ctx, len(wallets), workers, func(i int) error {
+		keystoreBytes, err := wallets[i].EncryptKeystore(passphrase, opts)
+		if err != nil {
+			return errors.Wrapf(err, "encrypting wallet %v", i)
+		}
+
+		w2, err := wallet.FromKeystore(keystoreBytes, passphrase)
+		if err != nil {
+			return errors.Wrapf(err, "decrypting keystore of wallet %v", i)
+		}
+		defer w2.Destroy()
+		if err := wallets[i].Equals(w2); err != nil {
+			return errors.Wrapf(err, "keystore of wallet %v does not contain it", i)
+		}
+
+		keystores[i] = keystoreBytes
+		if encrypted != nil {
+			atomic.AddUint64(encrypted, 1)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keystores, nil
+}
+
+// WriteCSV writes records to w as CSV with a header row. The private_key column is only present if the records include private keys.
+func WriteCSV(w io.Writer, records []Record) error {
+	withPrivateKeys := hasPrivateKeys(records)
+
+	cw := csv.NewWriter(w)
+	header := []string{"index", "address", "public_key"}
+	if withPrivateKeys {
+		header = append(header, "private_key")
+	}
+	if err := cw.Write(header); err != nil {
+		return errors.Wrap(err, "writing csv header")
+	}
+	for _, r := range records {
+		row := []string{strconv.Itoa(r.Index), r.Address, r.PublicKey}
+		if withPrivateKeys {
+			row = append(row, r.PrivateKey)
+		}
+		if err := cw.Write(row); err != nil {
+			return errors.Wrapf(err, "writing csv row %v", r.Index)
+		}
+	}
+	cw.Flush()
+
+	return errors.Wrap(cw.Error(), "flushing csv")
+}
+
+// WriteJSONL writes records to w as JSON Lines, one JSON object per line.
+func WriteJSONL(w io.Writer, records []Record) error {
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return errors.Wrapf(err, "writing record %v", r.Index)
+		}
+	}
+	return nil
+}
+
+// WriteKeystores writes keystores, as EncryptKeystores returns, to w as JSON Lines, one keystore per line in the same order as the records they belong to.
+// Any line can be saved on its own as a regular keystore file.
+func WriteKeystores(w io.Writer, keystores [][]byte) error {
+	for i, keystoreBytes := range keystores {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, keystoreBytes); err != nil {
+			return errors.Wrapf(err, "compacting keystore %v", i)
+		}
+		buf.WriteByte('\n')
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return errors.Wrapf(err, "writing keystore %v", i)
+		}
+	}
+	return nil
+}
+
+// parallel calls f once for every index in [0, n) across workers goroutines, stopping at the first error, which is returned.
+// If ctx is cancelled before every index is done, ctx.Err() is returned.
+func parallel(ctx context.Context, n, workers int, f func(i int) error) error {
+	if workers < 1 {
+		return fmt.Errorf("must run with at least 1 worker, got %v", workers)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var next, done int64 = -1, 0
+	failed := make(chan error, 1)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n {
+					return
+				}
+				if err := f(i); err != nil {
+					// Only the first error is kept, the rest of the workers stop as soon as they see the cancellation.
+					select {
+					case failed <- err:
+					default:
+					}
+					cancel()
+					return
+				}
+				atomic.AddInt64(&done, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case err := <-failed:
+		return err
+	default:
+		if int(atomic.LoadInt64(&done)) < n {
+			return ctx.Err()
+		}
+		return nil
+	}
+}
+
+// hasPrivateKeys reports whether any of records includes a private key.
+func hasPrivateKeys(records []Record) bool {
+	for _, r := range records {
+		if r.PrivateKey != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Generate(t *testing.T) {
+	var generated uint64
+
+	wallets, err := Generate(context.Background(), 50, 4, &generated)
+	require.NoError(t, err)
+	require.Len(t, wallets, 50)
+	assert.Equal(t, uint64(50), generated)
+
+	seen := make(map[string]bool)
+	for _, w := range wallets {
+		require.NotNil(t, w)
+		assert.NoError(t, w.Validate())
+		assert.False(t, seen[w.Address()], "duplicate wallet %s", w.Address())
+		seen[w.Address()] = true
+	}
+}
+
+func Test_Generate_Invalid(t *testing.T) {
+	_, err := Generate(context.Background(), 0, 4, nil)
+	assert.Error(t, err)
+
+	_, err = Generate(context.Background(), 10, 0, nil)
+	assert.Error(t, err)
+}
+
+func Test_Generate_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Generate(ctx, 10, 4, nil)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func Test_WriteCSV(t *testing.T) {
+	w := wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+
+	tests := []struct {
+		name               string
+		includePrivateKeys bool
+		header             []string
+		row                []string
+	}{
+		{
+			name:   "public only",
+			header: []string{"index", "address", "public_key"},
+			row:    []string{"0", wallettest.Address, w.PublicKeyHex()},
+		},
+		{
+			name:               "with private keys",
+			includePrivateKeys: true,
+			header:             []string{"index", "address", "public_key", "private_key"},
+			row:                []string{"0", wallettest.Address, w.PublicKeyHex(), wallettest.PrivateKeyHex},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteCSV(&buf, NewRecords([]*wallet.Wallet{w}, test.includePrivateKeys)))
+
+			rows, err := csv.NewReader(&buf).ReadAll()
+			require.NoError(t, err)
+			assert.Equal(t, [][]string{test.header, test.row}, rows)
+		})
+	}
+}
+
+func Test_WriteJSONL(t *testing.T) {
+	w := wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSONL(&buf, NewRecords([]*wallet.Wallet{w, w}, false)))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	for i, line := range lines {
+		var r Record
+		require.NoError(t, json.Unmarshal(line, &r))
+		assert.Equal(t, Record{Index: i, Address: wallettest.Address, PublicKey: w.PublicKeyHex()}, r)
+	}
+	assert.NotContains(t, buf.String(), wallettest.PrivateKeyHex)
+}
+
+func Test_EncryptKeystores(t *testing.T) {
+	wallets, err := Generate(context.Background(), 3, 2, nil)
+	require.NoError(t, err)
+
+	var encrypted uint64
+	keystores, err := EncryptKeystores(context.Background(), wallets, "passphrase", wallet.LightKeystoreOptions, 2, &encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), encrypted)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteKeystores(&buf, keystores))
+
+	scanner := bufio.NewScanner(&buf)
+	i := 0
+	for scanner.Scan() {
+		w, err := wallet.FromKeystore(scanner.Bytes(), "passphrase")
+		require.NoError(t, err)
+		assert.NoError(t, wallets[i].Equals(w))
+		i++
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, 3, i)
+}
@@ -0,0 +1,112 @@
+package checkbalance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/convert"
+	"github.com/Insulince/jeth/pkg/eth"
+	"github.com/Insulince/jeth/pkg/price"
+	"github.com/Insulince/jeth/pkg/store"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/pkg/errors"
+)
+
+// Command is "jeth check-balance".
+var Command = &cli.Command{
+	Name:    "check-balance",
+	Summary: "print a wallet's balance in wei, ether, and dollars",
+	Args:    "[flags]",
+	Description: `
+Prints the balance of a wallet, given by its address, a name in the store, or its public key.
+No private key is ever needed. Dollars are only shown on mainnet, at the current price, "?" (null with "-output json") if it cannot be fetched.`,
+	Examples: []string{
+		"check-balance -address 0x19325d2D5c17AF1096D28A12850D27bD182612F6",
+		"check-balance -address @savings",
+		"check-balance -address @savings -output json",
+	},
+	JSON: true,
+	Run:  run,
+}
+
+// result is check-balance's "-output json" result.
+type result struct {
+	Address wallet.Address `json:"address"`
+	Wei     string         `json:"wei"`
+	Eth     string         `json:"eth"`
+	// Usd is nil if ether could not be priced, which it never is off mainnet.
+	Usd *string `json:"usd"`
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	var addressRef string
+	var publicKeyHex string
+
+	env.Flags.StringVar(&addressRef, "address", "", "the wallet address whose balance you wish to check, or \"@name\" of a wallet or contact in the store, mixed case addresses must have a valid EIP-55 checksum")
+	env.Flags.StringVar(&publicKeyHex, "public-key", "", "the hexadecimal public key of the wallet whose balance you wish to check, instead of -address")
+	env.Parse(args)
+
+	addressBook, err := store.OpenDefault()
+	if err != nil {
+		panic(errors.Wrap(err, "opening store"))
+	}
+
+	// Checking a balance never needs a private key, so only a watch-only wallet is ever made.
+	var w *wallet.Wallet
+	switch {
+	case addressRef != "" && publicKeyHex != "":
+		panic(errors.New("must provide only one of -address or -public-key"))
+	case addressRef != "":
+		address, _, err := addressBook.Resolve(addressRef)
+		if err != nil {
+			panic(errors.Wrap(err, "resolving -address"))
+		}
+		w = wallet.WatchOnly(address)
+	case publicKeyHex != "":
+		w, err = wallet.WatchOnlyFromPublicKeyHex(publicKeyHex)
+		if err != nil {
+			panic(errors.Wrap(err, "parsing public key"))
+		}
+	default:
+		panic(errors.New("address cannot be blank, please provide a valid address via -address or a public key via -public-key"))
+	}
+
+	client, err := env.Dial(ctx)
+	if err != nil {
+		panic(err)
+	}
+	defer client.Close()
+
+	balance, err := client.BalanceAt(ctx, w.Addr().Common(), eth.LatestBlock)
+	if err != nil {
+		panic(errors.Wrap(err, "fetching account balance"))
+	}
+
+	eth := convert.WeiIToEth(balance)
+	var usd *string
+	if env.Chain.IsMainnet() {
+		if usdPerEth, err := price.UsdPerEth(); err == nil {
+			s := convert.EthToUsd(eth, usdPerEth).String()
+			usd = &s
+		}
+	}
+	if env.JSON() {
+		cli.PrintJSON(result{
+			Address: w.Addr(),
+			Wei:     balance.String(),
+			Eth:     eth.String(),
+			Usd:     usd,
+		})
+		return
+	}
+	fmt.Println(addressBook.Display(w.Addr()))
+	fmt.Println(balance)
+	fmt.Println(eth.String())
+	if usd == nil {
+		fmt.Println("?")
+		return
+	}
+	fmt.Println(*usd)
+}
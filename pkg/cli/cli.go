@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Exit codes shared by every command. A command may document further codes of its own, see Command.ExitCodes.
+const (
+	// ExitOK means the command succeeded.
+	ExitOK = 0
+	// ExitError means something went wrong, the error is printed to stderr.
+	ExitError = 1
+	// ExitUsage means the flags or arguments were invalid, the usage is printed to stderr.
+	ExitUsage = 2
+	// ExitRejected means the command ran but what it checked did not pass, such as a signature which is invalid or a transaction which would revert.
+	ExitRejected = 3
+	// ExitInterrupted means the command was stopped with Ctrl-C.
+	ExitInterrupted = 130
+)
+
+// Command is a subcommand of jeth. Each command is also built as its own binary, see MainCommand.
+type Command struct {
+	// Name is what the command is run as, "jeth <name>".
+	Name string
+	// Summary is a one line description, shown in jeth's list of commands.
+	Summary string
+	// Args describes the command's arguments after "jeth <name>", such as "[flags]".
+	Args string
+	// Description explains the command in full, shown in its usage.
+	Description string
+	// Examples are complete example invocations without the leading "jeth ", shown in its usage.
+	Examples []string
+	// ExitCodes describes the exit codes the command uses beyond ExitOK, ExitError, and ExitUsage.
+	ExitCodes map[int]string
+	// JSON reports whether the command supports "-output json".
+	JSON bool
+	// Run runs the command with its arguments, the flags after "jeth <name>". It registers its flags on env.Flags and then calls env.Parse.
+	// Errors are reported by panicking with an error, which is printed and exits with ExitError, or with Usagef or Exit.
+	Run func(ctx context.Context, env *Env, args []string)
+}
+
+// exitCode is panicked by Exit, so that deferred calls still run before the process exits.
+type exitCode int
+
+// usageError is panicked by Usagef.
+type usageError struct {
+	error
+}
+
+// Exit stops the running command with code once every deferred call has run, unlike os.Exit.
+func Exit(code int) {
+	panic(exitCode(code))
+}
+
+// Usagef stops the running command with ExitUsage, printing the formatted message and how to get help.
+func Usagef(format string, args ...interface{}) {
+	panic(usageError{fmt.Errorf(format, args...)})
+}
+
+// Main runs jeth, dispatching to one of commands by the first argument after the global flags.
+func Main(commands []*Command) {
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+
+	g := DefaultGlobals()
+	fs := flag.NewFlagSet("jeth", flag.ContinueOnError)
+	g.Register(fs)
+	fs.Usage = func() { printMainUsage(fs.Output(), fs, commands) }
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(ExitOK)
+		}
+		os.Exit(ExitUsage)
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(ExitUsage)
+	}
+
+	name, args := fs.Arg(0), fs.Args()[1:]
+	if name == "help" {
+		if len(args) == 0 {
+			printMainUsage(os.Stdout, fs, commands)
+			os.Exit(ExitOK)
+		}
+		name, args = args[0], []string{"-help"}
+	}
+	for _, c := range commands {
+		if c.Name == name {
+			os.Exit(run(c, "jeth "+c.Name, g, args))
+		}
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "jeth: unknown command \"%s\"\n\n", name)
+	fs.Usage()
+	os.Exit(ExitUsage)
+}
+
+// MainCommand runs c on its own, as the standalone binary named after it. It takes the same flags as "jeth <name>", global flags included.
+func MainCommand(c *Command) {
+	os.Exit(run(c, c.Name, DefaultGlobals(), os.Args[1:]))
+}
+
+// run runs c as program with the global flags g already parsed, returning the exit code.
+func run(c *Command, program string, g Globals, args []string) (code int) {
+	env := &Env{
+		Globals: g,
+		command: c,
+		program: program,
+		Flags:   flag.NewFlagSet(program, flag.ContinueOnError),
+	}
+	env.Flags.Usage = func() { printCommandUsage(env.Flags.Output(), env) }
+
+	defer func() {
+		r := recover()
+		switch r := r.(type) {
+		case nil:
+		case exitCode:
+			code = int(r)
+		case usageError:
+			_, _ = fmt.Fprintf(os.Stderr, "%s: %v\nrun \"%s -help\" for usage\n", program, r.error, program)
+			code = ExitUsage
+		case error:
+			_, _ = fmt.Fprintf(os.Stderr, "%s: error: %v\n", program, r)
+			code = ExitError
+		default:
+			panic(r)
+		}
+	}()
+
+	c.Run(context.Background(), env, args)
+	return ExitOK
+}
+
+// Env is what a running command is given: the global flags, and a flag set to register its own flags on.
+type Env struct {
+	Globals
+	// Flags is the command's flag set, the global flags are registered on it by Parse.
+	Flags *flag.FlagSet
+
+	command *Command
+	program string
+}
+
+// Parse registers the global flags on env.Flags, so they may also be given after the command name, and parses args.
+// "-help" prints the usage and exits with ExitOK, invalid flags exit with ExitUsage, as does "-output json" for a command which does not support it.
+func (env *Env) Parse(args []string) {
+	env.Globals.Register(env.Flags)
+	if err := env.Flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			Exit(ExitOK)
+		}
+		Exit(ExitUsage)
+	}
+	if env.JSON() && !env.command.JSON {
+		Usagef("\"-output %s\" is not supported", OutputJSON)
+	}
+}
+
+// Program returns what the command is being run as, such as "jeth send" or "send".
+func (env *Env) Program() string {
+	return env.program
+}
+
+// printMainUsage prints jeth's usage, listing every command, to w.
+func printMainUsage(w io.Writer, fs *flag.FlagSet, commands []*Command) {
+	_, _ = fmt.Fprintf(w, "usage: jeth [global flags] <command> [flags]\n\njeth manages ethereum wallets, keys, and transactions.\n\ncommands:\n")
+	width := 0
+	for _, c := range commands {
+		if len(c.Name) > width {
+			width = len(c.Name)
+		}
+	}
+	for _, c := range commands {
+		_, _ = fmt.Fprintf(w, "  %-*s  %s\n", width, c.Name, c.Summary)
+	}
+	_, _ = fmt.Fprintf(w, "\nglobal flags, which may also be given after the command:\n")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+	_, _ = fmt.Fprintf(w, "\nRun \"jeth help <command>\" or \"jeth <command> -help\" for a command's usage, examples, and exit codes.\n")
+}
+
+// printCommandUsage prints the usage of env's command to w, its own flags and the global flags listed separately.
+func printCommandUsage(w io.Writer, env *Env) {
+	c := env.command
+	_, _ = fmt.Fprintf(w, "usage: %s %s\n\n%s\n", env.program, c.Args, strings.TrimSpace(c.Description))
+
+	own := flag.NewFlagSet(env.program, flag.ContinueOnError)
+	globals := flag.NewFlagSet(env.program, flag.ContinueOnError)
+	env.Flags.VisitAll(func(f *flag.Flag) {
+		if isGlobal(f.Name) {
+			globals.Var(f.Value, f.Name, f.Usage)
+		} else {
+			own.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+	own.SetOutput(w)
+	globals.SetOutput(w)
+
+	hasOwn := false
+	own.VisitAll(func(*flag.Flag) { hasOwn = true })
+	if hasOwn {
+		_, _ = fmt.Fprintf(w, "\nflags:\n")
+		own.PrintDefaults()
+	}
+	_, _ = fmt.Fprintf(w, "\nglobal flags:\n")
+	globals.PrintDefaults()
+
+	if len(c.Examples) > 0 {
+		_, _ = fmt.Fprintf(w, "\nexamples:\n")
+		for _, example := range c.Examples {
+			_, _ = fmt.Fprintf(w, "  jeth %s\n", example)
+		}
+	}
+
+	exitCodes := map[int]string{
+		ExitOK:    "success",
+		ExitError: "error",
+		ExitUsage: "invalid flags or arguments",
+	}
+	for code, meaning := range c.ExitCodes {
+		exitCodes[code] = meaning
+	}
+	codes := make([]int, 0, len(exitCodes))
+	for code := range exitCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	_, _ = fmt.Fprintf(w, "\nexit codes:\n")
+	for _, code := range codes {
+		_, _ = fmt.Fprintf(w, "  %-3v  %s\n", code, exitCodes[code])
+	}
+}
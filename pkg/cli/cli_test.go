@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_run(t *testing.T) {
+	tests := []struct {
+		name     string
+		json     bool
+		args     []string
+		f        func()
+		expected int
+	}{
+		{
+			name:     "success",
+			expected: ExitOK,
+		},
+		{
+			name:     "help",
+			args:     []string{"-help"},
+			expected: ExitOK,
+		},
+		{
+			name:     "unknown flag",
+			args:     []string{"-bogus"},
+			expected: ExitUsage,
+		},
+		{
+			name:     "json not supported",
+			args:     []string{"-output", "json"},
+			expected: ExitUsage,
+		},
+		{
+			name:     "json supported",
+			json:     true,
+			args:     []string{"-output", "json"},
+			expected: ExitOK,
+		},
+		{
+			name:     "error",
+			f:        func() { panic(errors.New("failed")) },
+			expected: ExitError,
+		},
+		{
+			name:     "usage",
+			f:        func() { Usagef("bad argument") },
+			expected: ExitUsage,
+		},
+		{
+			name:     "exit",
+			f:        func() { Exit(ExitRejected) },
+			expected: ExitRejected,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Command{
+				Name: "test",
+				JSON: test.json,
+				Run: func(ctx context.Context, env *Env, args []string) {
+					env.Parse(args)
+					if test.f != nil {
+						test.f()
+					}
+				},
+			}
+			assert.Equal(t, test.expected, run(c, "jeth test", DefaultGlobals(), test.args))
+		})
+	}
+}
+
+func Test_Env_Parse(t *testing.T) {
+	var got *Env
+	c := &Command{
+		Name: "test",
+		Run: func(ctx context.Context, env *Env, args []string) {
+			var amount int
+			env.Flags.IntVar(&amount, "amount", 0, "")
+			env.Parse(args)
+			assert.Equal(t, 5, amount)
+			got = env
+		},
+	}
+
+	// The global flags may also be given after the command name, among the command's own flags.
+	code := run(c, "jeth test", DefaultGlobals(), []string{"-amount", "5", "-gateway", "http://localhost:8545", "-chain", "sepolia"})
+	assert.Equal(t, ExitOK, code)
+	assert.Equal(t, "http://localhost:8545", got.Gateway)
+	assert.Equal(t, "sepolia", got.Chain.Name)
+	assert.Equal(t, "jeth test", got.Program())
+}
@@ -0,0 +1,187 @@
+package contractaddress
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/store"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	// fetchNonce means -nonce was not given, so the pending nonce is fetched from the gateway.
+	fetchNonce = -1
+)
+
+// Command is "jeth contract-address".
+var Command = &cli.Command{
+	Name:    "contract-address",
+	Summary: "predict the addresses contracts will be deployed to, by CREATE or CREATE2",
+	Args:    "[flags]",
+	Description: `
+Prints the addresses the next contracts deployed by a wallet will have, one for each of its upcoming nonces.
+With -salt, prints instead the CREATE2 address a factory contract deploys the given init code to.`,
+	Examples: []string{
+		"contract-address -address @deployer",
+		"contract-address -address 0x19325d2D5c17AF1096D28A12850D27bD182612F6 -nonce 0 -count 3",
+		"contract-address -address @factory -salt 0x01 -init-code-file init-code.hex",
+	},
+	JSON: true,
+	Run:  run,
+}
+
+// createResult is contract-address's "-output json" result for CREATE addresses.
+type createResult struct {
+	Deployer  wallet.Address  `json:"deployer"`
+	Addresses []createAddress `json:"addresses"`
+}
+
+// createAddress is the address a contract deployed with Nonce has.
+type createAddress struct {
+	Nonce   uint64         `json:"nonce"`
+	Address wallet.Address `json:"address"`
+}
+
+// create2Result is contract-address's "-output json" result for a CREATE2 address.
+type create2Result struct {
+	Deployer     wallet.Address `json:"deployer"`
+	Salt         string         `json:"salt"`
+	InitCodeHash string         `json:"initCodeHash"`
+	Address      wallet.Address `json:"address"`
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	var addressRef string
+	var count int
+	var nonce int64
+	var saltHex string
+	var initCodeHashHex string
+	var initCodeFile string
+
+	env.Flags.StringVar(&addressRef, "address", "", "the deploying wallet's address, or \"@name\" of a wallet in the store, for CREATE2 the factory contract's address [required]")
+	env.Flags.IntVar(&count, "count", 5, "the number of upcoming CREATE addresses to print, starting at the next nonce")
+	env.Flags.Int64Var(&nonce, "nonce", fetchNonce, "the nonce to start from, if not given the address's pending nonce is fetched from -gateway")
+	env.Flags.StringVar(&saltHex, "salt", "", "compute a CREATE2 address instead, with this \"0x\" prefixed salt of up to 32 bytes, left padded with zeros")
+	env.Flags.StringVar(&initCodeHashHex, "init-code-hash", "", "for CREATE2, the \"0x\" prefixed Keccak-256 hash of the contract's init code (its creation bytecode and constructor arguments)")
+	env.Flags.StringVar(&initCodeFile, "init-code-file", "", "for CREATE2, path to a file holding the contract's \"0x\" prefixed hexadecimal init code, instead of -init-code-hash")
+	env.Parse(args)
+
+	if addressRef == "" {
+		panic(errors.New("address cannot be blank, please provide the deployer's address via -address"))
+	}
+	addressBook, err := store.OpenDefault()
+	if err != nil {
+		panic(errors.Wrap(err, "opening store"))
+	}
+	deployer, _, err := addressBook.Resolve(addressRef)
+	if err != nil {
+		panic(errors.Wrap(err, "resolving -address"))
+	}
+
+	if saltHex != "" {
+		create2(env, deployer, addressBook, saltHex, initCodeHashHex, initCodeFile)
+		return
+	}
+	if initCodeHashHex != "" || initCodeFile != "" {
+		panic(errors.New("-init-code-hash and -init-code-file are only used for CREATE2, please also provide -salt"))
+	}
+
+	if count < 1 {
+		panic(fmt.Errorf("count must be at least 1, got %v", count))
+	}
+	if nonce < fetchNonce {
+		panic(fmt.Errorf("nonce must not be negative, got %v", nonce))
+	}
+
+	if nonce == fetchNonce {
+		client, err := env.Dial(ctx)
+		if err != nil {
+			panic(err)
+		}
+		defer client.Close()
+
+		// The pending nonce counts transactions still in the mempool, so it is the nonce the next transaction will actually use.
+		pendingNonce, err := client.PendingNonceAt(ctx, deployer.Common())
+		if err != nil {
+			panic(errors.Wrapf(err, "fetching latest pending nonce for \"%s\"", deployer))
+		}
+		nonce = int64(pendingNonce)
+	}
+
+	if env.JSON() {
+		r := createResult{Deployer: deployer}
+		for i := 0; i < count; i++ {
+			n := uint64(nonce) + uint64(i)
+			r.Addresses = append(r.Addresses, createAddress{Nonce: n, Address: deployer.CreateAddress(n)})
+		}
+		cli.PrintJSON(r)
+		return
+	}
+
+	fmt.Printf("DEPLOYER:\n%s\n\nNEXT %v CREATE ADDRESSES (contract creation transactions sent from the deployer):\n", addressBook.Display(deployer), count)
+	for i := 0; i < count; i++ {
+		n := uint64(nonce) + uint64(i)
+		fmt.Printf("nonce %v: %s\n", n, deployer.CreateAddress(n))
+	}
+	fmt.Printf("\nAny other transaction sent from the deployer first uses up a nonce and shifts every address above.\n")
+}
+
+// create2 prints the CREATE2 address deployer deploys to given saltHex and either initCodeHashHex or the init code in initCodeFile.
+func create2(env *cli.Env, deployer wallet.Address, addressBook *store.Store, saltHex, initCodeHashHex, initCodeFile string) {
+	saltBytes, err := hexutil.Decode(saltHex)
+	if err != nil {
+		panic(errors.Wrap(err, "decoding -salt"))
+	}
+	if len(saltBytes) > common.HashLength {
+		panic(fmt.Errorf("salt must be at most %v bytes, got %v", common.HashLength, len(saltBytes)))
+	}
+	var salt [32]byte
+	copy(salt[common.HashLength-len(saltBytes):], saltBytes)
+
+	var initCodeHash []byte
+	switch {
+	case initCodeHashHex != "" && initCodeFile != "":
+		panic(errors.New("must provide only one of -init-code-hash or -init-code-file"))
+	case initCodeHashHex != "":
+		initCodeHash, err = hexutil.Decode(initCodeHashHex)
+		if err != nil {
+			panic(errors.Wrap(err, "decoding -init-code-hash"))
+		}
+	case initCodeFile != "":
+		bs, err := ioutil.ReadFile(initCodeFile)
+		if err != nil {
+			panic(errors.Wrap(err, "reading init code file"))
+		}
+		initCode, err := hexutil.Decode(strings.TrimSpace(string(bs)))
+		if err != nil {
+			panic(errors.Wrap(err, "decoding init code file"))
+		}
+		initCodeHash = wallet.InitCodeHash(initCode)
+	default:
+		panic(errors.New("CREATE2 needs the contract's init code, please provide -init-code-hash or -init-code-file"))
+	}
+
+	address, err := deployer.Create2Address(salt, initCodeHash)
+	if err != nil {
+		panic(errors.Wrap(err, "computing CREATE2 address"))
+	}
+
+	if env.JSON() {
+		cli.PrintJSON(create2Result{
+			Deployer:     deployer,
+			Salt:         hexutil.Encode(salt[:]),
+			InitCodeHash: hexutil.Encode(initCodeHash),
+			Address:      address,
+		})
+		return
+	}
+
+	fmt.Printf("DEPLOYER:\n%s\n\nSALT:\n%s\n\nINIT CODE HASH:\n%s\n\nCREATE2 ADDRESS:\n%s\n", addressBook.Display(deployer), hexutil.Encode(salt[:]), hexutil.Encode(initCodeHash), address)
+}
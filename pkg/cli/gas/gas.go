@@ -0,0 +1,52 @@
+package gas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Insulince/jeth/pkg/cli"
+
+	"github.com/pkg/errors"
+)
+
+// Command is "jeth gas".
+var Command = &cli.Command{
+	Name:    "gas",
+	Summary: "print the gateway's suggested gas price",
+	Args:    "[flags]",
+	Description: `
+Prints the gas price, in wei, which the gateway suggests for a legacy transaction.`,
+	Examples: []string{
+		"gas",
+		"gas -gateway https://rpc.sepolia.org -chain sepolia",
+		"gas -output json",
+	},
+	JSON: true,
+	Run:  run,
+}
+
+// result is gas's "-output json" result.
+type result struct {
+	GasPrice string `json:"gasPrice"`
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	env.Parse(args)
+
+	client, err := env.Dial(ctx)
+	if err != nil {
+		panic(err)
+	}
+	defer client.Close()
+
+	bGasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		panic(errors.Wrap(err, "getting suggested gas price"))
+	}
+
+	if env.JSON() {
+		cli.PrintJSON(result{GasPrice: bGasPrice.String()})
+		return
+	}
+	fmt.Println(bGasPrice.String())
+}
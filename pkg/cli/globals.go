@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/Insulince/jeth/pkg/eth"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+)
+
+// Output is the format a command prints its result in.
+type Output string
+
+const (
+	// OutputText is human readable text, the default.
+	OutputText Output = "text"
+	// OutputJSON is a single JSON document, for scripts. Only commands with Command.JSON set support it.
+	OutputJSON Output = "json"
+)
+
+// String implements flag.Value.
+func (o *Output) String() string {
+	return string(*o)
+}
+
+// Set implements flag.Value.
+func (o *Output) Set(value string) error {
+	switch Output(value) {
+	case OutputText, OutputJSON:
+		*o = Output(value)
+		return nil
+	default:
+		return fmt.Errorf("unknown output format \"%s\", must be \"%s\" or \"%s\"", value, OutputText, OutputJSON)
+	}
+}
+
+// Chain is an ethereum network, identified by its EIP-155 chain id.
+type Chain struct {
+	Name string
+	ID   *big.Int
+}
+
+// Chains are the networks which may be given to "-chain" by name, any other network may be given by its chain id.
+var Chains = []Chain{
+	{Name: "mainnet", ID: big.NewInt(1)},
+	{Name: "goerli", ID: big.NewInt(5)},
+	{Name: "sepolia", ID: big.NewInt(11155111)},
+	{Name: "holesky", ID: big.NewInt(17000)},
+}
+
+// ParseChain parses s, either the name of one of Chains or a chain id in decimal.
+func ParseChain(s string) (Chain, error) {
+	for _, c := range Chains {
+		if strings.EqualFold(c.Name, s) {
+			return c, nil
+		}
+	}
+
+	id, ok := new(big.Int).SetString(s, 10)
+	if !ok || id.Sign() <= 0 {
+		names := make([]string, len(Chains))
+		for i, c := range Chains {
+			names[i] = fmt.Sprintf("\"%s\"", c.Name)
+		}
+		return Chain{}, fmt.Errorf("unknown chain \"%s\", must be one of %s, or a positive chain id", s, strings.Join(names, ", "))
+	}
+	for _, c := range Chains {
+		if c.ID.Cmp(id) == 0 {
+			return c, nil
+		}
+	}
+	return Chain{Name: id.String(), ID: id}, nil
+}
+
+// String implements flag.Value.
+func (c *Chain) String() string {
+	return c.Name
+}
+
+// Set implements flag.Value.
+func (c *Chain) Set(value string) error {
+	chain, err := ParseChain(value)
+	if err != nil {
+		return err
+	}
+	*c = chain
+	return nil
+}
+
+// Describe returns c's name alongside its chain id, for showing to a person.
+func (c Chain) Describe() string {
+	if c.Name == c.ID.String() {
+		return fmt.Sprintf("chain id %s", c.ID)
+	}
+	return fmt.Sprintf("%s (chain id %s)", c.Name, c.ID)
+}
+
+// IsMainnet reports whether c is mainnet, the only chain whose ether has a dollar value.
+func (c Chain) IsMainnet() bool {
+	return c.ID.Cmp(Chains[0].ID) == 0
+}
+
+// Globals are the flags every command takes, given either before or after the command name.
+type Globals struct {
+	// Gateway is the connection to the ethereum provider.
+	Gateway string
+	// Output is the format to print the result in.
+	Output Output
+	// Chain is the network the gateway must be connected to, and which transactions are signed for.
+	Chain Chain
+}
+
+// globalNames are the names of the flags Globals.Register registers.
+var globalNames = []string{"gateway", "output", "chain"}
+
+// DefaultGlobals returns the global flags' defaults.
+func DefaultGlobals() Globals {
+	return Globals{
+		Gateway: eth.DefaultGateway,
+		Output:  OutputText,
+		Chain:   Chains[0],
+	}
+}
+
+// Register registers the global flags on fs, defaulting to g's current values.
+func (g *Globals) Register(fs *flag.FlagSet) {
+	fs.StringVar(&g.Gateway, "gateway", g.Gateway, "the connection to your ethereum provider")
+	fs.Var(&g.Output, "output", fmt.Sprintf("the format to print the result in, \"%s\" or \"%s\" for commands which support it", OutputText, OutputJSON))
+	fs.Var(&g.Chain, "chain", "the network to use, \"mainnet\", \"goerli\", \"sepolia\", \"holesky\", or any chain id, the gateway must be connected to it")
+}
+
+// JSON reports whether the result should be printed as JSON.
+func (g Globals) JSON() bool {
+	return g.Output == OutputJSON
+}
+
+// Dial connects to g.Gateway, checking that it is connected to g.Chain so nothing is ever read from or signed for the wrong network by mistake.
+func (g Globals) Dial(ctx context.Context) (*ethclient.Client, error) {
+	if g.Gateway == "" {
+		return nil, fmt.Errorf("must provide a non-blank ethereum gateway via \"-gateway\", or leave blank to use the default gateway, %s", eth.DefaultGateway)
+	}
+
+	client, err := ethclient.Dial(g.Gateway)
+	if err != nil {
+		return nil, errors.Wrap(err, "dialing eth gateway")
+	}
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		client.Close()
+		return nil, errors.Wrap(err, "fetching gateway's chain id")
+	}
+	if chainID.Cmp(g.Chain.ID) != 0 {
+		client.Close()
+		return nil, fmt.Errorf("gateway %s is connected to chain id %s, but \"-chain\" is %s", g.Gateway, chainID, g.Chain.Describe())
+	}
+
+	return client, nil
+}
+
+// PrintJSON prints v to stdout as indented JSON, for "-output json".
+func PrintJSON(v interface{}) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		panic(errors.Wrap(err, "marshalling json output"))
+	}
+	_, _ = fmt.Fprintln(os.Stdout, string(b))
+}
+
+// isGlobal reports whether name is the name of a global flag.
+func isGlobal(name string) bool {
+	for _, n := range globalNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
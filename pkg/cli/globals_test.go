@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseChain(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected Chain
+		err      bool
+	}{
+		{
+			name:     "name",
+			s:        "sepolia",
+			expected: Chain{Name: "sepolia", ID: big.NewInt(11155111)},
+		},
+		{
+			name:     "name ignores case",
+			s:        "Mainnet",
+			expected: Chain{Name: "mainnet", ID: big.NewInt(1)},
+		},
+		{
+			name:     "id of a known chain",
+			s:        "5",
+			expected: Chain{Name: "goerli", ID: big.NewInt(5)},
+		},
+		{
+			name:     "id of an unknown chain",
+			s:        "31337",
+			expected: Chain{Name: "31337", ID: big.NewInt(31337)},
+		},
+		{
+			name: "unknown name",
+			s:    "ropsten",
+			err:  true,
+		},
+		{
+			name: "zero",
+			s:    "0",
+			err:  true,
+		},
+		{
+			name: "negative",
+			s:    "-1",
+			err:  true,
+		},
+		{
+			name: "blank",
+			s:    "",
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chain, err := ParseChain(test.s)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, chain)
+		})
+	}
+}
+
+func Test_Chain_IsMainnet(t *testing.T) {
+	tests := []struct {
+		chain    string
+		expected bool
+	}{
+		{chain: "mainnet", expected: true},
+		{chain: "1", expected: true},
+		{chain: "sepolia", expected: false},
+		{chain: "31337", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.chain, func(t *testing.T) {
+			chain, err := ParseChain(test.chain)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, chain.IsMainnet())
+		})
+	}
+}
+
+func Test_Output_Set(t *testing.T) {
+	var o Output
+	require.NoError(t, o.Set("json"))
+	assert.Equal(t, OutputJSON, o)
+	require.NoError(t, o.Set("text"))
+	assert.Equal(t, OutputText, o)
+	assert.Error(t, o.Set("yaml"))
+}
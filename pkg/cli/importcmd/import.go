@@ -0,0 +1,187 @@
+package importcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/importer"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/pkg/errors"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+var (
+	// errSkipped means the user chose not to import a Candidate.
+	errSkipped = errors.New("skipped")
+)
+
+// Command is "jeth import".
+var Command = &cli.Command{
+	Name:    "import",
+	Summary: "find, decrypt, and validate old keystore files, presale wallets, and raw private keys",
+	Args:    "[flags]",
+	Description: `
+Searches files and directories for keystore files, presale wallets, and raw private key files, decrypts each one, and reports which wallets were found.
+Every wallet which works is validated, duplicates are reported once, and all of them may be re-exported as keystore files under one new passphrase.`,
+	Examples: []string{
+		"import -path ~/.ethereum/keystore -list-only",
+		"import -path ~/.ethereum/keystore -path ~/old-keys -export-dir ./keystores",
+	},
+	Run: run,
+}
+
+// outcome is what became of a Candidate, for the final report.
+type outcome struct {
+	candidate importer.Candidate
+	status    string
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	var paths cli.StringsFlag
+	var listOnly bool
+	var exportDir string
+	var kdf string
+
+	env.Flags.Var(&paths, "path", "a file, or a directory such as geth's keystore/ to search, for keystore files, presale wallets, and raw private key files, may be given any number of times [required]")
+	env.Flags.BoolVar(&listOnly, "list-only", false, "only list the keys found, without decrypting them")
+	env.Flags.StringVar(&exportDir, "export-dir", "", "if set, re-export every unique imported wallet into this directory as a keystore file, all encrypted with one new passphrase")
+	env.Flags.StringVar(&kdf, "kdf", string(wallet.KDFScrypt), "the key derivation function to use for exported keystore files, \"scrypt\" or \"pbkdf2\"")
+	env.Parse(args)
+
+	if len(paths) == 0 {
+		panic(errors.New("must provide at least one file or directory to import from via -path"))
+	}
+	opts, err := cli.KeystoreOptions(kdf)
+	if err != nil {
+		panic(err)
+	}
+
+	var candidates []importer.Candidate
+	for _, path := range paths {
+		found, err := importer.Scan(path)
+		if err != nil {
+			panic(errors.Wrapf(err, "scanning \"%s\"", path))
+		}
+		candidates = append(candidates, found...)
+	}
+	fmt.Printf("FOUND %v KEY FILES:\n", len(candidates))
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, c := range candidates {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Format, claimedAddress(c), c.Path)
+	}
+	_ = tw.Flush()
+	if listOnly || len(candidates) == 0 {
+		return
+	}
+
+	im := importer.New()
+	defer im.Destroy()
+
+	// Old keys are often all encrypted with the same passphrase, so every passphrase which has worked is tried before prompting for another.
+	var passphrases []string
+	var outcomes []outcome
+	for _, c := range candidates {
+		r, err := importCandidate(im, c, &passphrases)
+		switch {
+		case errors.Is(err, errSkipped):
+			outcomes = append(outcomes, outcome{candidate: c, status: "skipped"})
+		case err != nil:
+			outcomes = append(outcomes, outcome{candidate: c, status: "failed"})
+		case r.DuplicateOf != "":
+			outcomes = append(outcomes, outcome{candidate: r.Candidate, status: fmt.Sprintf("duplicate of %s", r.DuplicateOf)})
+		default:
+			outcomes = append(outcomes, outcome{candidate: r.Candidate, status: "imported"})
+		}
+	}
+
+	fmt.Printf("\nIMPORT RESULTS:\n")
+	tw = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "STATUS\tFORMAT\tADDRESS\tPATH")
+	for _, o := range outcomes {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", o.status, o.candidate.Format, claimedAddress(o.candidate), o.candidate.Path)
+	}
+	_ = tw.Flush()
+
+	unique := im.Unique()
+	fmt.Printf("\n%v unique wallets imported, %v duplicates, %v skipped or failed. Every imported wallet has been validated.\n", len(unique), len(im.Results())-len(unique), len(candidates)-len(im.Results()))
+
+	if exportDir != "" && len(unique) > 0 {
+		export(unique, exportDir, opts)
+	}
+}
+
+// importCandidate imports c into im, trying each of passphrases and then prompting for more until one works or the user gives up with a blank one.
+// A passphrase which works is added to passphrases.
+func importCandidate(im *importer.Importer, c importer.Candidate, passphrases *[]string) (importer.Result, error) {
+	if !c.NeedsPassphrase() {
+		r, err := im.Import(c, "")
+		if err != nil {
+			jio.Outputf("could not import %s: %v\n", c.Path, err)
+		}
+		return r, err
+	}
+
+	for _, passphrase := range *passphrases {
+		if r, err := im.Import(c, passphrase); err == nil {
+			return r, nil
+		}
+	}
+
+	for {
+		passphrase := jio.MustPrivateInputWithPrompt(fmt.Sprintf("enter the passphrase for %s %s (%s), or leave blank to skip it: ", c.Format, c.Path, claimedAddress(c)))
+		jio.SilentOutputln("")
+		if passphrase == "" {
+			return importer.Result{}, errSkipped
+		}
+
+		r, err := im.Import(c, passphrase)
+		if err != nil {
+			jio.Outputf("could not import %s: %v\n", c.Path, err)
+			continue
+		}
+		*passphrases = append(*passphrases, passphrase)
+		return r, nil
+	}
+}
+
+// export writes every result into dir as a keystore file encrypted with a passphrase prompted for on stdin, reading each back to be certain it decrypts.
+func export(results []importer.Result, dir string, opts wallet.KeystoreOptions) {
+	fmt.Println()
+	passphrase, err := cli.NewPassphrase("the exported keystore files")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("\nEXPORTED KEYSTORE FILES:\n")
+	for _, r := range results {
+		path, err := r.Wallet.WriteKeystoreFile(dir, passphrase, opts)
+		if err != nil {
+			panic(errors.Wrapf(err, "exporting %s", r.Wallet.Address()))
+		}
+
+		w2, err := wallet.FromKeystoreFile(path, passphrase)
+		if err != nil {
+			panic(errors.Wrapf(err, "reading back exported keystore file \"%s\"", path))
+		}
+		if err := r.Wallet.Equals(w2); err != nil {
+			panic(errors.Wrapf(err, "exported keystore file \"%s\" does not contain %s", path, r.Wallet.Address()))
+		}
+		w2.Destroy()
+
+		fmt.Printf("%s  %s\n", r.Wallet.Address(), path)
+	}
+	fmt.Printf("\nEvery exported keystore file has been read back and decrypts to its wallet. Check them before deleting any originals.\n")
+}
+
+// claimedAddress returns the address c claims to hold, or a placeholder if it does not record one.
+func claimedAddress(c importer.Candidate) string {
+	if c.Address.IsZero() {
+		return "(address unknown until decrypted)"
+	}
+	return c.Address.Hex()
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/Insulince/jeth/pkg/signer"
+	"github.com/Insulince/jeth/pkg/store"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+// StringsFlag is a flag which may be given any number of times, collecting every value.
+type StringsFlag []string
+
+// String implements flag.Value.
+func (f *StringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set implements flag.Value.
+func (f *StringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// SignedMessage is the JSON format MyEtherWallet and MyCrypto use to share a signed message, sign-message prints it and verify-message accepts it.
+type SignedMessage struct {
+	Address   wallet.Address `json:"address"`
+	Message   string         `json:"msg"`
+	Signature string         `json:"sig"`
+	Version   string         `json:"version"`
+}
+
+// LoadWallet loads the wallet for privateKeyHex, or for keystorePath if it is given instead, which may also be "@name" of a keystore-backed wallet in addressBook.
+// addressBook may be nil for commands which do not use the store, keystorePath is then always a path.
+// If neither is given, the private key is prompted for on stdin.
+func LoadWallet(addressBook *store.Store, privateKeyHex, keystorePath string) (*wallet.Wallet, error) {
+	if privateKeyHex != "" && keystorePath != "" {
+		return nil, errors.New("must provide only one of \"-private-key\" or \"-keystore\"")
+	}
+
+	if keystorePath != "" {
+		keystorePath, err := ResolveKeystore(addressBook, keystorePath)
+		if err != nil {
+			return nil, err
+		}
+		passphrase := jio.MustPrivateInputWithPrompt(fmt.Sprintf("enter the passphrase for keystore file \"%s\": ", keystorePath))
+		jio.SilentOutputln("")
+		w, err := wallet.FromKeystoreFile(keystorePath, passphrase)
+		if err != nil {
+			return nil, errors.Wrap(err, "decrypting keystore file")
+		}
+		return w, nil
+	}
+
+	if privateKeyHex == "" {
+		privateKeyHex = jio.MustPrivateInputWithPrompt("private key not given via \"-private-key\" flag, enter manually instead: ")
+		jio.SilentOutputln("")
+	}
+	w, err := wallet.FromPrivateKeyHex(privateKeyHex)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing private key")
+	}
+	return w, nil
+}
+
+// ResolveKeystore returns keystorePath, or the path of the keystore file in addressBook if it is "@name" of a keystore-backed wallet.
+func ResolveKeystore(addressBook *store.Store, keystorePath string) (string, error) {
+	if addressBook == nil || !strings.HasPrefix(keystorePath, store.RefPrefix) {
+		return keystorePath, nil
+	}
+
+	e, err := addressBook.Get(keystorePath)
+	if err != nil {
+		return "", errors.Wrap(err, "looking up \"-keystore\" in store")
+	}
+	if !e.IsKeystoreBacked() {
+		return "", fmt.Errorf("\"%s\" is not a keystore-backed wallet, so it cannot be given via \"-keystore\"", keystorePath)
+	}
+	return addressBook.KeystorePath(e), nil
+}
+
+// DestroySigner wipes s's key material from memory if it holds any, see signer.Destroyer. It is safe to call more than once.
+func DestroySigner(s signer.Signer) {
+	if d, ok := s.(signer.Destroyer); ok {
+		d.Destroy()
+	}
+}
+
+// CloseSigner closes s's connection if it holds one open, see signer.Closer.
+func CloseSigner(s signer.Signer) {
+	if c, ok := s.(signer.Closer); ok {
+		c.Close()
+	}
+}
+
+// NewPassphrase prompts on stdin for a new passphrase to encrypt what with, twice to be certain it was typed as intended.
+func NewPassphrase(what string) (string, error) {
+	passphrase := jio.MustPrivateInputWithPrompt(fmt.Sprintf("enter a passphrase to encrypt %s with: ", what))
+	jio.SilentOutputln("")
+	confirmation := jio.MustPrivateInputWithPrompt("enter the passphrase again to confirm: ")
+	jio.SilentOutputln("")
+	if passphrase != confirmation {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// KeystoreOptions returns wallet.StandardKeystoreOptions using kdf, as given via "-kdf".
+func KeystoreOptions(kdf string) (wallet.KeystoreOptions, error) {
+	opts := wallet.StandardKeystoreOptions
+	opts.KDF = wallet.KDF(kdf)
+	if opts.KDF != wallet.KDFScrypt && opts.KDF != wallet.KDFPBKDF2 {
+		return wallet.KeystoreOptions{}, fmt.Errorf("unsupported kdf \"%s\", please provide \"%s\" or \"%s\" via -kdf", kdf, wallet.KDFScrypt, wallet.KDFPBKDF2)
+	}
+	return opts, nil
+}
+
+// LoadMessage returns message, or the contents of messageFile if it is given instead. If neither is given, the message is prompted for on stdin.
+// If isHex is set, the message is decoded from "0x" prefixed hexadecimal.
+func LoadMessage(message, messageFile string, isHex bool) ([]byte, error) {
+	if message != "" && messageFile != "" {
+		return nil, errors.New("must provide only one of \"-message\" or \"-message-file\"")
+	}
+
+	if messageFile != "" {
+		bs, err := ioutil.ReadFile(messageFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading message file")
+		}
+		message = string(bs)
+	}
+	if message == "" && messageFile == "" {
+		// The message may contain spaces, so read the whole line rather than a single word.
+		jio.Output("message not given via \"-message\" flag, enter manually instead: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, errors.Wrap(err, "reading message from stdin")
+		}
+		message = strings.TrimRight(line, "\r\n")
+	}
+
+	if isHex {
+		bs, err := hexutil.Decode(message)
+		if err != nil {
+			return nil, errors.Wrap(err, "decoding hexadecimal message")
+		}
+		return bs, nil
+	}
+	return []byte(message), nil
+}
@@ -0,0 +1,191 @@
+package newwallet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/Insulince/jeth/pkg/bulk"
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/pkg/errors"
+)
+
+// encryptMemoryBudget is roughly the most memory the bulk encryption workers may use at once, scrypt with the standard parameters takes 256MB per keystore.
+const encryptMemoryBudget = 1 << 30
+
+// generateBulk generates count wallets across workers goroutines and writes them to outPath, as CSV or JSON Lines depending on its extension.
+// If keysOutPath is set the private keys are written there instead, as JSON Lines of keystores encrypted with a passphrase prompted for on stdin,
+// so that outPath holds only public data and can be shared. Otherwise the private keys are written to outPath alongside everything else.
+// Neither file may already exist, a previous batch's private keys are never overwritten.
+// Ctrl-C stops the workers and exits without writing anything.
+func generateBulk(ctx context.Context, count, workers int, outPath, keysOutPath string, opts wallet.KeystoreOptions) {
+	// Checked up front so that nothing is generated only to be thrown away, the files are still created exclusively when written.
+	for _, path := range []string{outPath, keysOutPath} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			panic(fmt.Errorf("\"%s\" already exists, it may hold a previous batch's only copy of its private keys, choose another path", path))
+		}
+	}
+
+	passphrase := ""
+	if keysOutPath != "" {
+		var err error
+		passphrase, err = cli.NewPassphrase("the private keys")
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	fmt.Printf("Generating %v wallets with %v workers (press Ctrl-C to stop)...\n", count, workers)
+	start := time.Now()
+
+	var generated uint64
+	var wallets []*wallet.Wallet
+	err := withProgress("generated", count, &generated, func() error {
+		var err error
+		wallets, err = bulk.Generate(ctx, count, workers, &generated)
+		return err
+	})
+	exitIfCancelled(err)
+	if err != nil {
+		panic(errors.Wrap(err, "generating wallets"))
+	}
+	defer func() {
+		for _, w := range wallets {
+			w.Destroy()
+		}
+	}()
+
+	records := bulk.NewRecords(wallets, keysOutPath == "")
+	var buf bytes.Buffer
+	if strings.ToLower(filepath.Ext(outPath)) == ".csv" {
+		err = bulk.WriteCSV(&buf, records)
+	} else {
+		err = bulk.WriteJSONL(&buf, records)
+	}
+	if err != nil {
+		panic(errors.Wrap(err, "rendering wallets"))
+	}
+
+	if keysOutPath == "" {
+		// Without -keys-out the private keys are in the output, only its owner may read it.
+		if err := writeNewFile(outPath, buf.Bytes(), 0600); err != nil {
+			panic(errors.Wrap(err, "writing wallets"))
+		}
+		fmt.Printf("\nWALLETS (WITH PRIVATE KEYS):\n%s\n\nEvery wallet has been validated and is well-formed and correct.\nThis file holds the private keys in plain text, do not share it, use -keys-out to keep them separate.\n\nSuccess! (%v)\n", outPath, time.Since(start))
+		return
+	}
+
+	encryptWorkers := workers
+	if memory := opts.Memory(); memory > 0 && encryptWorkers > encryptMemoryBudget/memory {
+		encryptWorkers = encryptMemoryBudget / memory
+		if encryptWorkers < 1 {
+			encryptWorkers = 1
+		}
+	}
+	fmt.Printf("\nEncrypting %v private keys with %v workers...\n", count, encryptWorkers)
+	var encrypted uint64
+	var keystores [][]byte
+	err = withProgress("encrypted", count, &encrypted, func() error {
+		var err error
+		keystores, err = bulk.EncryptKeystores(ctx, wallets, passphrase, opts, encryptWorkers, &encrypted)
+		return err
+	})
+	exitIfCancelled(err)
+	if err != nil {
+		panic(errors.Wrap(err, "encrypting private keys"))
+	}
+
+	var keysBuf bytes.Buffer
+	if err := bulk.WriteKeystores(&keysBuf, keystores); err != nil {
+		panic(errors.Wrap(err, "rendering keystores"))
+	}
+	if err := writeNewFile(keysOutPath, keysBuf.Bytes(), 0600); err != nil {
+		panic(errors.Wrap(err, "writing keystores"))
+	}
+	if err := writeNewFile(outPath, buf.Bytes(), 0644); err != nil {
+		panic(errors.Wrap(err, "writing wallets"))
+	}
+
+	fmt.Printf("\nWALLETS (PUBLIC ONLY, SAFE TO SHARE):\n%s\n\nENCRYPTED PRIVATE KEYS (one keystore per line, in the same order):\n%s\n\nEvery wallet has been validated and every keystore has been decrypted again to be certain it holds its wallet.\n\nSuccess! (%v)\n", outPath, keysOutPath, time.Since(start))
+}
+
+// writeNewFile writes data to path, which must not exist yet, with perm.
+func writeNewFile(path string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// samePath reports whether a and b are the same path once made absolute.
+func samePath(a, b string) (bool, error) {
+	absA, err := filepath.Abs(a)
+	if err != nil {
+		return false, errors.Wrapf(err, "resolving \"%s\"", a)
+	}
+	absB, err := filepath.Abs(b)
+	if err != nil {
+		return false, errors.Wrapf(err, "resolving \"%s\"", b)
+	}
+	return absA == absB, nil
+}
+
+// withProgress runs f, printing every second how many of count are done according to done.
+func withProgress(verb string, count int, done *uint64, f func() error) error {
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fmt.Printf("%v of %v %s\n", atomic.LoadUint64(done), count, verb)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	err := f()
+	close(stop)
+	return err
+}
+
+// exitIfCancelled exits if err means the user stopped the workers with Ctrl-C.
+func exitIfCancelled(err error) {
+	if err == context.Canceled {
+		fmt.Printf("\nStopped, nothing was written.\n")
+		cli.Exit(cli.ExitInterrupted)
+	}
+}
@@ -0,0 +1,281 @@
+package newwallet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/paper"
+	"github.com/Insulince/jeth/pkg/vanity"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/pkg/errors"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+const (
+	paperSecretMnemonic   = "mnemonic"
+	paperSecretPrivateKey = "private-key"
+)
+
+// Command is "jeth new-wallet".
+var Command = &cli.Command{
+	Name:    "new-wallet",
+	Summary: "generate a new wallet, a vanity wallet, or wallets in bulk",
+	Args:    "[flags]",
+	Description: `
+Generates a new wallet from a BIP-39 mnemonic, showing its mnemonic, private key, public key, address, and account extended public key.
+With the vanity flags, searches instead for a wallet whose address matches a pattern. With -bulk, generates many wallets at once into a file.
+Every wallet is validated, and may also be written as a keystore file or rendered as a paper wallet.`,
+	Examples: []string{
+		"new-wallet",
+		"new-wallet -words 24 -mnemonic-passphrase -keystore-dir ./keystores",
+		"new-wallet -vanity-prefix cafe -paper wallet.pdf",
+		"new-wallet -bulk 1000 -out wallets.csv -keys-out keys.jsonl",
+	},
+	ExitCodes: map[int]string{
+		cli.ExitInterrupted: "stopped with Ctrl-C before a vanity wallet was found or bulk wallets were written",
+	},
+	Run: run,
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	var words int
+	var usePassphrase bool
+	var keystoreDir string
+	var kdf string
+	var vanityPrefix string
+	var vanitySuffix string
+	var vanityRegex string
+	var vanityCaseSensitive bool
+	var workers int
+	var paperPath string
+	var paperSecret string
+	var bulkCount int
+	var outPath string
+	var keysOutPath string
+	var lightKDF bool
+
+	env.Flags.IntVar(&words, "words", 12, "the number of words in the generated mnemonic, 12, 15, 18, 21, or 24")
+	env.Flags.BoolVar(&usePassphrase, "mnemonic-passphrase", false, "prompt for an optional BIP-39 passphrase to protect the mnemonic with, it will be needed alongside the mnemonic to restore the wallet")
+	env.Flags.StringVar(&keystoreDir, "keystore-dir", "", "if set, also write the new wallet into this directory as a passphrase encrypted keystore file")
+	env.Flags.StringVar(&kdf, "kdf", string(wallet.KDFScrypt), "the key derivation function to use for the keystore file, \"scrypt\" or \"pbkdf2\"")
+	env.Flags.StringVar(&vanityPrefix, "vanity-prefix", "", "search for a wallet whose address starts with this hexadecimal prefix (excluding \"0x\"), vanity wallets have no mnemonic")
+	env.Flags.StringVar(&vanitySuffix, "vanity-suffix", "", "search for a wallet whose address ends with this hexadecimal suffix")
+	env.Flags.StringVar(&vanityRegex, "vanity-regex", "", "search for a wallet whose checksummed address (excluding \"0x\") matches this regular expression, prefix with \"(?i)\" to ignore case")
+	env.Flags.BoolVar(&vanityCaseSensitive, "vanity-case-sensitive", false, "match -vanity-prefix and -vanity-suffix against the EIP-55 checksummed casing of the address, each letter doubles the difficulty")
+	env.Flags.IntVar(&workers, "workers", runtime.NumCPU(), "the number of goroutines to search for a vanity address, or generate and encrypt bulk wallets, with, encryption uses fewer if scrypt would need more than 1GB of memory across them")
+	env.Flags.StringVar(&paperPath, "paper", "", "if set, also render a printable paper wallet to this path, which must not exist yet, as a PDF or PNG depending on its extension, \".pdf\" or \".png\"")
+	env.Flags.StringVar(&paperSecret, "paper-secret", paperSecretMnemonic, fmt.Sprintf("the secret to show on the paper wallet, \"%s\" or \"%s\", vanity wallets have no mnemonic so always show their private key", paperSecretMnemonic, paperSecretPrivateKey))
+	env.Flags.IntVar(&bulkCount, "bulk", 0, "generate this many wallets at once and write them to -out instead, bulk wallets have no mnemonic")
+	env.Flags.StringVar(&outPath, "out", "", "for -bulk, the path to write the wallets to, as CSV or JSON Lines depending on its extension, \".csv\" or \".jsonl\"")
+	env.Flags.StringVar(&keysOutPath, "keys-out", "", "for -bulk, the path to write the private keys to instead, as JSON Lines of passphrase encrypted keystores, so that -out holds only public data and can be shared")
+	env.Flags.BoolVar(&lightKDF, "light-kdf", false, "for -keys-out, use geth's \"light\" key derivation parameters, much faster to encrypt thousands of keys but also much faster to brute force")
+	env.Parse(args)
+
+	if paperPath != "" {
+		if ext := strings.ToLower(filepath.Ext(paperPath)); ext != ".pdf" && ext != ".png" {
+			panic(fmt.Errorf("unsupported paper wallet format \"%s\", please provide a path ending in \".pdf\" or \".png\" via -paper", ext))
+		}
+		if _, err := os.Stat(paperPath); err == nil {
+			panic(fmt.Errorf("\"%s\" already exists, it may be another wallet's only paper copy, choose another path via -paper", paperPath))
+		}
+	}
+	if paperSecret != paperSecretMnemonic && paperSecret != paperSecretPrivateKey {
+		panic(fmt.Errorf("unsupported paper wallet secret \"%s\", please provide \"%s\" or \"%s\" via -paper-secret", paperSecret, paperSecretMnemonic, paperSecretPrivateKey))
+	}
+
+	opts, err := cli.KeystoreOptions(kdf)
+	if err != nil {
+		panic(err)
+	}
+
+	if lightKDF {
+		kdf := opts.KDF
+		opts = wallet.LightKeystoreOptions
+		opts.KDF = kdf
+	}
+
+	if bulkCount != 0 {
+		if bulkCount < 1 {
+			panic(fmt.Errorf("bulk must be at least 1, got %v", bulkCount))
+		}
+		if ext := strings.ToLower(filepath.Ext(outPath)); ext != ".csv" && ext != ".jsonl" {
+			panic(fmt.Errorf("unsupported bulk output format \"%s\", please provide a path ending in \".csv\" or \".jsonl\" via -out", ext))
+		}
+		if vanityPrefix != "" || vanitySuffix != "" || vanityRegex != "" || usePassphrase || keystoreDir != "" || paperPath != "" {
+			panic(errors.New("-bulk cannot be combined with vanity, mnemonic, -keystore-dir, or -paper flags"))
+		}
+		if keysOutPath != "" {
+			same, err := samePath(outPath, keysOutPath)
+			if err != nil {
+				panic(err)
+			}
+			if same {
+				cli.Usagef("-out and -keys-out must be different files, otherwise the wallets overwrite the encrypted private keys")
+			}
+		}
+
+		generateBulk(ctx, bulkCount, workers, outPath, keysOutPath, opts)
+		return
+	}
+	if outPath != "" || keysOutPath != "" {
+		panic(errors.New("-out and -keys-out are only used with -bulk"))
+	}
+
+	if vanityPrefix != "" || vanitySuffix != "" || vanityRegex != "" {
+		pattern := vanity.Pattern{
+			Prefix:        vanityPrefix,
+			Suffix:        vanitySuffix,
+			CaseSensitive: vanityCaseSensitive,
+		}
+		if vanityRegex != "" {
+			regex, err := regexp.Compile(vanityRegex)
+			if err != nil {
+				panic(errors.Wrap(err, "compiling -vanity-regex"))
+			}
+			pattern.Regex = regex
+		}
+
+		w := searchVanity(ctx, pattern, workers)
+		writeKeystore(w, keystoreDir, opts)
+		writePaper(paperPath, paper.Content{Address: w.Address(), SecretLabel: "private key", Secret: w.PrivateKeyHex()})
+		return
+	}
+
+	mnemonicPassphrase := ""
+	if usePassphrase {
+		mnemonicPassphrase = jio.MustPrivateInputWithPrompt("enter a passphrase to protect the mnemonic with: ")
+		jio.SilentOutputln("")
+		confirmation := jio.MustPrivateInputWithPrompt("enter the passphrase again to confirm: ")
+		jio.SilentOutputln("")
+		if mnemonicPassphrase != confirmation {
+			panic(errors.New("passphrases do not match"))
+		}
+	}
+
+	fmt.Printf("Generating a new Ethereum mnemonic, private key, public key, and wallet address...\n")
+	start := time.Now()
+
+	w, mnemonic, err := wallet.NewWithMnemonic(words, mnemonicPassphrase)
+	if err != nil {
+		panic(errors.Wrap(err, "generating wallet"))
+	}
+
+	if err := w.Validate(); err != nil {
+		panic(errors.Wrap(err, "generated wallet is invalid"))
+	}
+
+	// The account's extended public key lets any number of deposit addresses be derived elsewhere without exposing a private key.
+	accountXpub, err := accountExtendedPublicKey(mnemonic, mnemonicPassphrase)
+	if err != nil {
+		panic(errors.Wrap(err, "deriving account extended public key"))
+	}
+
+	duration := time.Since(start)
+	fmt.Printf("\nMNEMONIC (%s, BIP-39 passphrase protected: %v):\n%s\n\nPRIVATE KEY:\n%s\n\nPUBLIC KEY:\n%s\n\nWALLET ADDRESS:\n%s\n\nACCOUNT EXTENDED PUBLIC KEY (%s, derives watch-only addresses only):\n%s\n\nWallet has been validated and is well-formed and correct.\n\nSuccess! (%v)\n", wallet.DefaultDerivationPath, usePassphrase, mnemonic, w.PrivateKeyHex(), w.PublicKeyHex(), w.Address(), wallet.DefaultAccountPath, accountXpub, duration)
+
+	writeKeystore(w, keystoreDir, opts)
+
+	content := paper.Content{Address: w.Address(), SecretLabel: "private key", Secret: w.PrivateKeyHex()}
+	if paperSecret == paperSecretMnemonic {
+		content = paper.Content{
+			Address:     w.Address(),
+			SecretLabel: "mnemonic",
+			Secret:      mnemonic,
+			Notes:       []string{fmt.Sprintf("BIP-39 mnemonic, %v words, address derived at %s", words, wallet.DefaultDerivationPath)},
+		}
+		if usePassphrase {
+			content.Notes = append(content.Notes, "protected by a BIP-39 passphrase which is NOT on this sheet, the mnemonic alone will not restore this wallet")
+		}
+	}
+	writePaper(paperPath, content)
+}
+
+// writePaper renders a paper wallet showing content to path, as a PDF or PNG depending on its extension. Nothing is written if path is blank.
+func writePaper(path string, content paper.Content) {
+	if path == "" {
+		return
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if strings.ToLower(filepath.Ext(path)) == ".png" {
+		err = paper.WritePNG(&buf, content)
+	} else {
+		err = paper.WritePDF(&buf, content)
+	}
+	if err != nil {
+		panic(errors.Wrap(err, "rendering paper wallet"))
+	}
+
+	// The paper wallet holds the secret in plain sight, only its owner may read it until it is printed and deleted.
+	// An existing file is never overwritten, it may be another wallet's only copy, and would keep its own, possibly wider, permissions.
+	if err := writeNewFile(path, buf.Bytes(), 0600); err != nil {
+		panic(errors.Wrap(err, "writing paper wallet"))
+	}
+
+	fmt.Printf("\nPAPER WALLET (%s):\n%s\n\nPrint it on a printer which is not networked if you can, then securely delete the file.\n", strings.ToUpper(content.SecretLabel), path)
+}
+
+// writeKeystore writes w into dir as a keystore file encrypted with a passphrase prompted for on stdin. Nothing is written if dir is blank.
+func writeKeystore(w *wallet.Wallet, dir string, opts wallet.KeystoreOptions) {
+	if dir == "" {
+		return
+	}
+
+	fmt.Println()
+	passphrase, err := cli.NewPassphrase("the keystore file")
+	if err != nil {
+		panic(err)
+	}
+
+	path, err := w.WriteKeystoreFile(dir, passphrase, opts)
+	if err != nil {
+		panic(errors.Wrap(err, "writing keystore file"))
+	}
+
+	// Read the file back to be certain the wallet can actually be recovered from it before anyone relies on it.
+	w2, err := wallet.FromKeystoreFile(path, passphrase)
+	if err != nil {
+		panic(errors.Wrap(err, "reading back keystore file"))
+	}
+	if err := w.Equals(w2); err != nil {
+		panic(errors.Wrap(err, "keystore file does not contain the generated wallet"))
+	}
+
+	fmt.Printf("\nKEYSTORE FILE:\n%s\n\nKeystore file has been read back and decrypts to the wallet above.\n", path)
+}
+
+// accountExtendedPublicKey returns the serialized extended public key at wallet.DefaultAccountPath of mnemonic and passphrase.
+func accountExtendedPublicKey(mnemonic, passphrase string) (string, error) {
+	seed, err := wallet.MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return "", errors.Wrap(err, "converting mnemonic to seed")
+	}
+
+	master, err := wallet.NewMasterKey(seed)
+	if err != nil {
+		return "", errors.Wrap(err, "deriving master key")
+	}
+
+	account, err := master.Derive(wallet.DefaultAccountPath)
+	if err != nil {
+		return "", errors.Wrap(err, "deriving account key")
+	}
+
+	accountPub, err := account.Neuter()
+	if err != nil {
+		return "", errors.Wrap(err, "neutering account key")
+	}
+
+	return accountPub.String(), nil
+}
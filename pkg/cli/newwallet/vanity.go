@@ -0,0 +1,100 @@
+package newwallet
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/vanity"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/pkg/errors"
+)
+
+// searchVanity searches for a wallet matching pattern, reporting progress every second until one is found.
+// Ctrl-C stops the workers and exits without a wallet.
+func searchVanity(ctx context.Context, pattern vanity.Pattern, workers int) *wallet.Wallet {
+	if err := pattern.Validate(); err != nil {
+		panic(errors.Wrap(err, "invalid vanity pattern"))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	difficulty := pattern.Difficulty()
+	fmt.Printf("Searching for a vanity wallet address with %v workers, expecting ~%.0f attempts (press Ctrl-C to stop)...\n", workers, difficulty)
+	if pattern.Regex != nil {
+		fmt.Printf("The difficulty of -vanity-regex cannot be estimated, expect more attempts than this.\n")
+	}
+	start := time.Now()
+
+	var attempts uint64
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				reportVanityProgress(atomic.LoadUint64(&attempts), time.Since(start), difficulty)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	w, err := vanity.Search(ctx, pattern, workers, &attempts)
+	close(done)
+	duration := time.Since(start)
+	if err == context.Canceled {
+		fmt.Printf("\nSearch stopped after %v attempts (%v), no matching wallet was found.\n", atomic.LoadUint64(&attempts), duration.Round(time.Millisecond))
+		cli.Exit(cli.ExitInterrupted)
+	}
+	if err != nil {
+		panic(errors.Wrap(err, "searching for vanity wallet"))
+	}
+
+	if err := w.Validate(); err != nil {
+		panic(errors.Wrap(err, "found wallet is invalid"))
+	}
+
+	fmt.Printf("\nPRIVATE KEY:\n%s\n\nPUBLIC KEY:\n%s\n\nWALLET ADDRESS:\n%s\n\nWallet has been validated and is well-formed and correct.\n\nSuccess! (%v attempts, %v)\n", w.PrivateKeyHex(), w.PublicKeyHex(), w.Address(), atomic.LoadUint64(&attempts), duration)
+
+	return w
+}
+
+// reportVanityProgress prints the attempt rate so far and how long a match is expected to take at that rate.
+func reportVanityProgress(attempts uint64, elapsed time.Duration, difficulty float64) {
+	rate := float64(attempts) / elapsed.Seconds()
+	if rate == 0 {
+		return
+	}
+	fmt.Printf("%v attempts (%.0f/s), %.1f%% of expected attempts, expected time to match at this rate: %s\n", attempts, rate, float64(attempts)/difficulty*100, expectedVanityTime(difficulty, rate))
+}
+
+// expectedVanityTime describes how long difficulty attempts take at rate attempts per second.
+// Beyond what a time.Duration holds, some 292 years, converting would overflow, and the search is hopeless anyway.
+func expectedVanityTime(difficulty, rate float64) string {
+	nanoseconds := difficulty / rate * float64(time.Second)
+	if nanoseconds >= math.MaxInt64 {
+		return "practically never (over 292 years)"
+	}
+	return time.Duration(nanoseconds).Round(time.Second).String()
+}
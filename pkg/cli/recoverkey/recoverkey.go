@@ -0,0 +1,84 @@
+package recoverkey
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/shamir"
+
+	"github.com/pkg/errors"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+// Command is "jeth recover-key".
+var Command = &cli.Command{
+	Name:    "recover-key",
+	Summary: "recover a private key from the shares split-key split it into",
+	Args:    "[flags]",
+	Description: `
+Recovers a wallet's private key from enough of the shares split-key split it into, prompting for any more shares needed.
+The recovered wallet is validated against the address recorded in the shares.`,
+	Examples: []string{
+		"recover-key",
+		"recover-key -share-file share-1.txt -share-file share-3.txt",
+	},
+	Run: run,
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	var encodedShares cli.StringsFlag
+	var shareFiles cli.StringsFlag
+
+	env.Flags.Var(&encodedShares, "share", fmt.Sprintf("a share, beginning with \"%s\", may be given any number of times [required via flag, -share-file, or stdin at runtime]", shamir.SharePrefix))
+	env.Flags.Var(&shareFiles, "share-file", "path to a file holding a share, may be given any number of times")
+	env.Parse(args)
+
+	for _, path := range shareFiles {
+		bs, err := ioutil.ReadFile(path)
+		if err != nil {
+			panic(errors.Wrapf(err, "reading share file \"%s\"", path))
+		}
+		encodedShares = append(encodedShares, string(bs))
+	}
+
+	var shares []shamir.Share
+	for i, encoded := range encodedShares {
+		s, err := shamir.ParseShare(encoded)
+		if err != nil {
+			panic(errors.Wrapf(err, "parsing share #%v given", i+1))
+		}
+		shares = append(shares, s)
+	}
+
+	// Prompt for shares until there are enough, the first share tells how many that is.
+	for len(shares) == 0 || len(shares) < shares[0].Threshold {
+		prompt := "enter a share: "
+		if len(shares) > 0 {
+			prompt = fmt.Sprintf("%v of %v shares needed given, enter another share: ", len(shares), shares[0].Threshold)
+		}
+		s, err := shamir.ParseShare(jio.MustPrivateInputWithPrompt(prompt))
+		jio.SilentOutputln("")
+		if err != nil {
+			// A mistyped share is the most likely mistake, let it be entered again rather than starting over.
+			jio.Outputf("invalid share, try again: %v\n", err)
+			continue
+		}
+		shares = append(shares, s)
+	}
+
+	fmt.Printf("Recovering the private key of %s from %v shares...\n", shares[0].Address, len(shares))
+
+	w, err := shamir.Combine(shares)
+	if err != nil {
+		panic(errors.Wrap(err, "recovering wallet from shares"))
+	}
+
+	if err := w.Validate(); err != nil {
+		panic(errors.Wrap(err, "recovered wallet is invalid"))
+	}
+
+	fmt.Printf("\nPRIVATE KEY:\n%s\n\nPUBLIC KEY:\n%s\n\nWALLET ADDRESS:\n%s\n\nWallet has been validated and matches the address the shares were split from.\n", w.PrivateKeyHex(), w.PublicKeyHex(), w.Address())
+}
@@ -0,0 +1,189 @@
+package recoversigner
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/convert"
+	"github.com/Insulince/jeth/pkg/store"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+// Command is "jeth recover-signer".
+var Command = &cli.Command{
+	Name:    "recover-signer",
+	Summary: "recover the public key and address which signed a transaction or hash",
+	Args:    "[flags]",
+	Description: `
+Recovers the public key and address of whoever signed a transaction, as send prints it or in its raw encoding, checking it was signed for -chain.
+With -hash and -signature, recovers the signer of a bare 32 byte hash instead.`,
+	Examples: []string{
+		"recover-signer -tx 0xf86c...",
+		"recover-signer -tx-file signed-tx.json -chain sepolia",
+		"recover-signer -tx-file old-tx.hex -any-chain",
+		"recover-signer -hash 0x... -signature 0x... -output json",
+	},
+	JSON: true,
+	Run:  run,
+}
+
+// result is recover-signer's "-output json" result.
+type result struct {
+	PublicKey string         `json:"publicKey"`
+	Address   wallet.Address `json:"address"`
+	// Transaction is only set when the signer of a transaction was recovered.
+	Transaction *transaction `json:"transaction,omitempty"`
+}
+
+// transaction describes the transaction whose signer was recovered.
+type transaction struct {
+	Hash string `json:"hash"`
+	Type string `json:"type"`
+	// ChainID is blank for a legacy transaction without EIP-155 replay protection.
+	ChainID string `json:"chainId,omitempty"`
+	Nonce   uint64 `json:"nonce"`
+	// To is nil for a contract creation.
+	To    *wallet.Address `json:"to"`
+	Value string          `json:"value"`
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	var txEncoded string
+	var txFile string
+	var anyChain bool
+	var hashHex string
+	var signatureHex string
+
+	env.Flags.StringVar(&txEncoded, "tx", "", "the signed transaction, either the json send prints or its \"0x\" prefixed raw encoding [required via flag, -tx-file, or stdin at runtime, unless -hash is given]")
+	env.Flags.StringVar(&txFile, "tx-file", "", "path to a file holding the signed transaction, in either form -tx accepts")
+	env.Flags.BoolVar(&anyChain, "any-chain", false, "accept a transaction for any chain, including a legacy transaction without EIP-155 replay protection, instead of only one for -chain")
+	env.Flags.StringVar(&hashHex, "hash", "", "recover the signer of this \"0x\" prefixed 32 byte hash instead of a transaction, requires -signature")
+	env.Flags.StringVar(&signatureHex, "signature", "", "for -hash, the \"0x\" prefixed 65 byte signature, r || s || v")
+	env.Parse(args)
+
+	addressBook, err := store.OpenDefault()
+	if err != nil {
+		panic(errors.Wrap(err, "opening store"))
+	}
+
+	if hashHex != "" {
+		if txEncoded != "" || txFile != "" {
+			panic(errors.New("must provide only one of a transaction or -hash"))
+		}
+		w := recoverHashSigner(hashHex, signatureHex)
+		if env.JSON() {
+			cli.PrintJSON(result{PublicKey: w.PublicKeyHex(), Address: w.Addr()})
+			return
+		}
+		fmt.Printf("SIGNER PUBLIC KEY:\n%s\n\nSIGNER ADDRESS:\n%s\n\nAny valid signature recovers to some signer, compare it to the signer you expect yourself.\n", w.PublicKeyHex(), addressBook.Display(w.Addr()))
+		return
+	}
+	if signatureHex != "" {
+		panic(errors.New("-signature is only used with -hash, a signed transaction carries its own signature"))
+	}
+
+	switch {
+	case txEncoded != "" && txFile != "":
+		panic(errors.New("must provide only one of -tx or -tx-file"))
+	case txFile != "":
+		bs, err := ioutil.ReadFile(txFile)
+		if err != nil {
+			panic(errors.Wrap(err, "reading transaction file"))
+		}
+		txEncoded = string(bs)
+	case txEncoded == "":
+		txEncoded = jio.MustInputWithPrompt("transaction not given via \"-tx\" flag, enter its \"0x\" prefixed raw encoding manually instead: ")
+	}
+
+	var expectedChainID *big.Int
+	if !anyChain {
+		expectedChainID = env.Chain.ID
+	}
+
+	w, tx, err := wallet.FromSignedTransaction([]byte(txEncoded), expectedChainID)
+	if err != nil {
+		panic(errors.Wrap(err, "recovering transaction signer"))
+	}
+
+	if env.JSON() {
+		r := result{
+			PublicKey: w.PublicKeyHex(),
+			Address:   w.Addr(),
+			Transaction: &transaction{
+				Hash:  tx.Hash().Hex(),
+				Type:  txTypeName(tx.Type()),
+				Nonce: tx.Nonce(),
+				Value: tx.Value().String(),
+			},
+		}
+		if tx.Protected() {
+			r.Transaction.ChainID = tx.ChainId().String()
+		}
+		if tx.To() != nil {
+			to := wallet.Address(*tx.To())
+			r.Transaction.To = &to
+		}
+		cli.PrintJSON(r)
+		return
+	}
+
+	to := "(contract creation)"
+	if tx.To() != nil {
+		to = addressBook.Display(wallet.Address(*tx.To()))
+	}
+	txChainID := "(none, not replay protected)"
+	if tx.Protected() {
+		txChainID = tx.ChainId().String()
+	}
+
+	fmt.Printf("TRANSACTION:\nhash: %s\ntype: %s\nchain id: %s\nnonce: %v\nto: %s\nvalue: %v eth\n\nSIGNER PUBLIC KEY:\n%s\n\nSIGNER ADDRESS:\n%s\n", tx.Hash().Hex(), txTypeName(tx.Type()), txChainID, tx.Nonce(), to, convert.WeiIToEth(tx.Value()).String(), w.PublicKeyHex(), addressBook.Display(w.Addr()))
+	if expectedChainID == nil {
+		fmt.Printf("\n-any-chain was given, so the transaction's chain was not checked.\n")
+	}
+}
+
+// recoverHashSigner recovers the signer of the hash hashHex from signatureHex.
+func recoverHashSigner(hashHex, signatureHex string) *wallet.Wallet {
+	if signatureHex == "" {
+		panic(errors.New("-hash needs the signature of the hash, please provide -signature"))
+	}
+
+	hash, err := hexutil.Decode(hashHex)
+	if err != nil {
+		panic(errors.Wrap(err, "decoding -hash"))
+	}
+	signature, err := hexutil.Decode(signatureHex)
+	if err != nil {
+		panic(errors.Wrap(err, "decoding -signature"))
+	}
+
+	w, err := wallet.FromSignature(hash, signature)
+	if err != nil {
+		panic(errors.Wrap(err, "recovering signer"))
+	}
+
+	return w
+}
+
+// txTypeName returns a human readable name for the EIP-2718 transaction type t.
+func txTypeName(t uint8) string {
+	switch t {
+	case types.LegacyTxType:
+		return "legacy"
+	case types.AccessListTxType:
+		return "access list (EIP-2930)"
+	case types.DynamicFeeTxType:
+		return "dynamic fee (EIP-1559)"
+	default:
+		return fmt.Sprintf("unknown (%v)", t)
+	}
+}
@@ -0,0 +1,93 @@
+package send
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jeth/pkg/convert"
+	"github.com/Insulince/jeth/pkg/erc20"
+	"github.com/Insulince/jeth/pkg/eth"
+)
+
+const (
+	// feeModeDeduct takes the fee out of the amount, so the sender never spends more than the amount and the receiver gets less.
+	feeModeDeduct = "deduct"
+	// feeModeOnTop pays the fee on top of the amount, so the receiver gets exactly the amount, such as when paying an invoice.
+	feeModeOnTop = "on-top"
+
+	// amountMax is given via "-amount" to send the whole balance minus the fee.
+	amountMax = "max"
+
+	// etherDecimals is the number of decimal places of ether, one ether is 10^18 wei.
+	etherDecimals = 18
+)
+
+// parseAmount parses s, as given via "-amount", into cfg, either an amount of ether into cfg.amount in wei, or of the token with "-token", or amountMax.
+// Both are parsed exactly, never through a float. A token amount is parsed from cfg.amountText once the token's decimals are known, see token.amount,
+// until then it is only checked to be a number.
+func parseAmount(cfg *Config, s string) error {
+	cfg.amountText = strings.TrimSpace(s)
+	if strings.EqualFold(cfg.amountText, amountMax) {
+		cfg.sendMax = true
+		return nil
+	}
+
+	decimals := uint8(etherDecimals)
+	if !cfg.token.IsZero() {
+		decimals = math.MaxUint8
+	}
+	amount, err := erc20.ParseUnits(cfg.amountText, decimals)
+	if err != nil {
+		return errors.Wrapf(err, "amount \"%s\" is neither a number nor \"%s\"", s, amountMax)
+	}
+	if amount.Sign() == 0 {
+		return errors.New("must provide a non-negative non-zero amount to send via \"-amount\" or at runtime via stdin")
+	}
+	if cfg.token.IsZero() {
+		cfg.amount = amount
+	}
+	return nil
+}
+
+// sendValue returns the value the receiver gets when the sender sends amount wei, paying at most fee wei, according to cfg, checking that balance covers everything the sender spends in the worst case.
+// amount is ignored when sending the whole balance. If balance does not cover it the returned error wraps eth.ErrInsufficientFunds.
+func sendValue(cfg Config, amount, balance, fee *big.Int) (*big.Int, error) {
+	switch {
+	case cfg.sendMax:
+		if balance.Cmp(fee) <= 0 {
+			return nil, errors.Wrapf(eth.ErrInsufficientFunds, "balance of %s wei cannot cover the worst-case fee of %s wei", balance, fee)
+		}
+		return new(big.Int).Sub(balance, fee), nil
+	case cfg.feeMode == feeModeOnTop:
+		total := new(big.Int).Add(amount, fee)
+		if balance.Cmp(total) < 0 {
+			return nil, errors.Wrapf(eth.ErrInsufficientFunds, "balance of %s wei cannot cover the amount of %s wei plus the worst-case fee of %s wei", balance, amount, fee)
+		}
+		return new(big.Int).Set(amount), nil
+	default:
+		if amount.Cmp(fee) <= 0 {
+			return nil, fmt.Errorf("the amount of %s wei does not cover the worst-case fee of %s wei taken out of it, send more or pay the fee on top via \"-fee-mode %s\"", amount, fee, feeModeOnTop)
+		}
+		if balance.Cmp(amount) < 0 {
+			return nil, errors.Wrapf(eth.ErrInsufficientFunds, "balance of %s wei cannot cover the amount of %s wei", balance, amount)
+		}
+		return new(big.Int).Sub(amount, fee), nil
+	}
+}
+
+// amountDescription describes the amount cfg sends, as given via "-amount".
+func amountDescription(cfg Config) string {
+	if cfg.sendMax {
+		return amountMax
+	}
+	return fmt.Sprintf("%s ether", erc20.FormatUnits(cfg.amount, etherDecimals))
+}
+
+// ether formats wei exactly in ether with its approximate value in dollars, for the summary.
+func ether(wei *big.Int, usdPerEth float64) string {
+	return fmt.Sprintf("%s ether ($%.2f)", erc20.FormatUnits(wei, etherDecimals), convert.F(convert.WeiIToUsd(wei, usdPerEth)))
+}
@@ -0,0 +1,186 @@
+package send
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Insulince/jeth/pkg/eth"
+	"github.com/Insulince/jeth/pkg/wallet"
+)
+
+func Test_parseAmount(t *testing.T) {
+	token := wallet.MustParseAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+	tests := []struct {
+		name     string
+		s        string
+		token    wallet.Address
+		expected string
+		sendMax  bool
+		err      bool
+	}{
+		{
+			name:     "ether",
+			s:        "1.1",
+			expected: "1100000000000000000",
+		},
+		{
+			name:     "ether, whole",
+			s:        " 2 ",
+			expected: "2000000000000000000",
+		},
+		{
+			name:     "ether, one wei",
+			s:        "0.000000000000000001",
+			expected: "1",
+		},
+		{
+			name: "ether, less than one wei",
+			s:    "0.0000000000000000001",
+			err:  true,
+		},
+		{
+			name:    "max",
+			s:       "MAX",
+			sendMax: true,
+		},
+		{
+			name:  "token, parsed once its decimals are known",
+			s:     "0.0000000000000000001",
+			token: token,
+		},
+		{
+			name: "zero",
+			s:    "0.0",
+			err:  true,
+		},
+		{
+			name: "negative",
+			s:    "-1",
+			err:  true,
+		},
+		{
+			name: "exponent",
+			s:    "1e18",
+			err:  true,
+		},
+		{
+			name: "not a number",
+			s:    "all",
+			err:  true,
+		},
+		{
+			name:  "token, not a number",
+			s:     "all",
+			token: token,
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := Config{token: test.token}
+			err := parseAmount(&cfg, test.s)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.sendMax, cfg.sendMax)
+			if test.expected == "" {
+				assert.Nil(t, cfg.amount)
+				return
+			}
+			require.NotNil(t, cfg.amount)
+			assert.Equal(t, test.expected, cfg.amount.String())
+		})
+	}
+}
+
+func Test_sendValue(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		amount       int64
+		balance      int64
+		fee          int64
+		expected     int64
+		insufficient bool
+		err          bool
+	}{
+		{
+			name:     "deduct",
+			cfg:      Config{feeMode: feeModeDeduct},
+			amount:   100,
+			balance:  100,
+			fee:      10,
+			expected: 90,
+		},
+		{
+			name:    "deduct, amount does not cover the fee",
+			cfg:     Config{feeMode: feeModeDeduct},
+			amount:  10,
+			balance: 100,
+			fee:     10,
+			err:     true,
+		},
+		{
+			name:         "deduct, insufficient balance",
+			cfg:          Config{feeMode: feeModeDeduct},
+			amount:       100,
+			balance:      99,
+			fee:          10,
+			insufficient: true,
+		},
+		{
+			name:     "on-top",
+			cfg:      Config{feeMode: feeModeOnTop},
+			amount:   90,
+			balance:  100,
+			fee:      10,
+			expected: 90,
+		},
+		{
+			name:         "on-top, insufficient balance",
+			cfg:          Config{feeMode: feeModeOnTop},
+			amount:       91,
+			balance:      100,
+			fee:          10,
+			insufficient: true,
+		},
+		{
+			name:     "max",
+			cfg:      Config{feeMode: feeModeDeduct, sendMax: true},
+			balance:  100,
+			fee:      10,
+			expected: 90,
+		},
+		{
+			name:         "max, insufficient balance",
+			cfg:          Config{feeMode: feeModeDeduct, sendMax: true},
+			balance:      10,
+			fee:          10,
+			insufficient: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := sendValue(test.cfg, big.NewInt(test.amount), big.NewInt(test.balance), big.NewInt(test.fee))
+			if test.insufficient {
+				assert.True(t, errors.Is(err, eth.ErrInsufficientFunds), "expected %v, got %v", eth.ErrInsufficientFunds, err)
+				return
+			}
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, big.NewInt(test.expected).String(), value.String())
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package send
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/eth"
+	"github.com/Insulince/jeth/pkg/signer"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+// registerSignerFlags registers the flags choosing how the transaction is signed on env.Flags, see checkSigner.
+func registerSignerFlags(env *cli.Env, cfg *Config, senderRef *string) {
+	env.Flags.StringVar(&cfg.signerKind, "signer", "", fmt.Sprintf("how to sign the transaction, \"%s\", \"%s\", or \"%s\" (a Clef-compatible signer), defaults to \"%s\" if \"-keystore\" is given and \"%s\" otherwise", signerPrivateKey, signerKeystore, signerRemote, signerKeystore, signerPrivateKey))
+	env.Flags.StringVar(&cfg.privateKeyHex, "private-key", "", "the hexadecimal private key of the sender's wallet, for the \"private-key\" signer [required via flag or stdin at runtime]")
+	env.Flags.StringVar(&cfg.keystorePath, "keystore", "", "the path to the sender's passphrase encrypted keystore file, or \"@name\" of a keystore-backed wallet in the store, for the \"keystore\" signer")
+	env.Flags.StringVar(&cfg.remoteSignerURL, "remote-signer-url", signer.DefaultRemoteURL, "the HTTP JSON-RPC endpoint of the \"remote\" signer")
+	env.Flags.StringVar(senderRef, "sender-address", "", "the sender's wallet address, or \"@name\" of a wallet in the store, for the \"remote\" signer to sign as, may be left blank if the remote signer only has one account")
+}
+
+// checkSigner checks the signer flags in cfg, prompting for a private key on stdin if the "private-key" signer was not given one, and resolves senderRef.
+func checkSigner(cfg *Config, senderRef string) (err error) {
+	if cfg.signerKind == "" {
+		cfg.signerKind = signerPrivateKey
+		if cfg.keystorePath != "" {
+			cfg.signerKind = signerKeystore
+		}
+	}
+	switch cfg.signerKind {
+	case signerPrivateKey:
+		if cfg.keystorePath != "" {
+			return errors.New("must provide only one of \"-private-key\" or \"-keystore\"")
+		}
+		if cfg.privateKeyHex == "" {
+			cfg.privateKeyHex = jio.MustPrivateInputWithPrompt("sender's private key not given via \"-private-key\" flag, enter manually instead: ")
+			jio.SilentOutputln("")
+		}
+		if len(cfg.privateKeyHex) != 64 {
+			return errors.New("must provide a 64 character hexadecimal private key via \"-private-key\" or at runtime via stdin")
+		}
+	case signerKeystore:
+		if cfg.privateKeyHex != "" {
+			return errors.New("must provide only one of \"-private-key\" or \"-keystore\"")
+		}
+		if cfg.keystorePath == "" {
+			return errors.New("must provide the path to a keystore file via \"-keystore\" for the \"keystore\" signer")
+		}
+		cfg.keystorePath, err = cli.ResolveKeystore(cfg.addressBook, cfg.keystorePath)
+		if err != nil {
+			return err
+		}
+	case signerRemote:
+		if cfg.privateKeyHex != "" || cfg.keystorePath != "" {
+			return errors.New("must not provide \"-private-key\" or \"-keystore\" for the \"remote\" signer, it holds its own keys")
+		}
+		if cfg.remoteSignerURL == "" {
+			return fmt.Errorf("must provide a non-blank remote signer url via \"-remote-signer-url\", or leave blank to use the default, %s", signer.DefaultRemoteURL)
+		}
+		if senderRef != "" {
+			cfg.senderWalletAddress, _, err = cfg.addressBook.Resolve(senderRef)
+			if err != nil {
+				return errors.Wrap(err, "resolving \"-sender-address\"")
+			}
+		}
+	default:
+		return fmt.Errorf("unknown signer \"%s\", must provide \"%s\", \"%s\", or \"%s\" via \"-signer\"", cfg.signerKind, signerPrivateKey, signerKeystore, signerRemote)
+	}
+	return nil
+}
+
+// resolveReceiver resolves receiverRef, as given via "-receiver-address", into cfg, prompting for it on stdin if it was not given.
+func resolveReceiver(cfg *Config, receiverRef string) (err error) {
+	if receiverRef == "" {
+		receiverRef = jio.MustInputWithPrompt("receiver's wallet address not given via \"-receiver-address\" flag, enter manually (or \"@name\") instead: ")
+	}
+	cfg.receiverWalletAddress, _, err = cfg.addressBook.Resolve(receiverRef)
+	if err != nil {
+		return errors.Wrap(err, "resolving receiver's wallet address")
+	}
+	if cfg.receiverWalletAddress.IsZero() {
+		return errors.New("must provide a wallet address other than the zero address for receiver via \"-receiver-address\" or at runtime via stdin, funds sent to the zero address are lost")
+	}
+	return nil
+}
+
+// registerTxFlags registers the flags choosing the transaction's type, fees, and gas limit on env.Flags, along with "-dry-run", see checkTxFlags.
+func registerTxFlags(env *cli.Env, cfg *Config) {
+	env.Flags.BoolVar(&cfg.legacy, "legacy", false, "send a legacy transaction with a single \"-gas-price\", for chains which do not support EIP-1559")
+	env.Flags.Int64Var(&cfg.gasPrice, "gas-price", defaultSuggestedFee, "for \"-legacy\", the gas price for your transaction in wei units, or \"0\" to use the network's suggested gas price")
+	env.Flags.Int64Var(&cfg.maxFee, "max-fee", defaultSuggestedFee, "the most to pay per unit of gas in wei units, base fee and priority fee combined, or \"0\" for twice the latest base fee plus the max priority fee")
+	env.Flags.Int64Var(&cfg.maxPriorityFee, "max-priority-fee", defaultSuggestedFee, "the most to tip the miner per unit of gas in wei units, or \"0\" to use the network's suggested priority fee")
+	env.Flags.Uint64Var(&cfg.gasLimit, "gas-limit", estimateGas, "the gas limit for your transaction, or \"0\" to estimate it with eth_estimateGas")
+	env.Flags.Uint64Var(&cfg.gasMargin, "gas-margin", defaultGasMargin, "the percentage to add on top of an estimated gas limit as a safety margin when the receiver is a contract, a plain transfer's estimate is exact so it is used as is")
+	env.Flags.BoolVar(&cfg.dryRun, "dry-run", false, "don't actually send the transaction, just build, sign, and display it, then simulate it against the gateway without prompting for confirmation, exiting non-zero if it would fail")
+}
+
+// checkTxFlags checks the flags registerTxFlags registers.
+func checkTxFlags(cfg Config) error {
+	if cfg.gasPrice < 0 {
+		return errors.New("must provide a non-negative gas price via \"-gas-price\" in wei units, or provide \"0\" or leave blank to choose the network's suggested gas price")
+	}
+	if cfg.maxFee < 0 || cfg.maxPriorityFee < 0 {
+		return errors.New("must provide a non-negative max fee and max priority fee via \"-max-fee\" and \"-max-priority-fee\" in wei units, or provide \"0\" or leave blank to choose them from the network")
+	}
+	if cfg.legacy && (cfg.maxFee != defaultSuggestedFee || cfg.maxPriorityFee != defaultSuggestedFee) {
+		return errors.New("\"-max-fee\" and \"-max-priority-fee\" are only used for EIP-1559 transactions, provide \"-gas-price\" instead with \"-legacy\"")
+	}
+	if !cfg.legacy && cfg.gasPrice != defaultSuggestedFee {
+		return errors.New("\"-gas-price\" is only used with \"-legacy\", EIP-1559 transactions take \"-max-fee\" and \"-max-priority-fee\" instead")
+	}
+	if cfg.gasLimit != estimateGas && cfg.gasLimit < transferGasLimit {
+		return fmt.Errorf("must provide a gas limit of at least %v via \"-gas-limit\", the gas of a plain transfer, or provide \"0\" or leave blank to estimate it", transferGasLimit)
+	}
+	return nil
+}
+
+// getSigner creates the signer cfg.signerKind describes, prompting for a keystore passphrase on stdin if necessary.
+func getSigner(ctx context.Context, cfg Config) (signer.Signer, error) {
+	switch cfg.signerKind {
+	case signerPrivateKey:
+		w, err := wallet.FromPrivateKeyHex(cfg.privateKeyHex)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing private key")
+		}
+		jio.Outputf("converted private key to ECDSA: [PRIVATE] %s\n", eth.ObfuscateKey(cfg.privateKeyHex))
+		jio.Outputf("sender's public key extracted from given private key: [PUBLIC] %s\n", w.PublicKeyHex())
+		return signer.NewWallet(w), nil
+	case signerKeystore:
+		passphrase := jio.MustPrivateInputWithPrompt(fmt.Sprintf("enter the passphrase for keystore file \"%s\": ", cfg.keystorePath))
+		jio.SilentOutputln("")
+		s, err := signer.NewKeystore(cfg.keystorePath, passphrase)
+		if err != nil {
+			return nil, errors.Wrap(err, "opening keystore file")
+		}
+		return s, nil
+	case signerRemote:
+		senderWalletAddress := ""
+		if !cfg.senderWalletAddress.IsZero() {
+			senderWalletAddress = cfg.senderWalletAddress.Hex()
+		}
+		s, err := signer.DialRemote(ctx, cfg.remoteSignerURL, senderWalletAddress)
+		if err != nil {
+			return nil, errors.Wrap(err, "connecting to remote signer")
+		}
+		jio.Outputf("connected to remote signer: %s\n", cfg.remoteSignerURL)
+		return s, nil
+	}
+	return nil, fmt.Errorf("unknown signer \"%s\"", cfg.signerKind)
+}
@@ -0,0 +1,79 @@
+package send
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jeth/pkg/eth"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+// simulate runs signedTx from sender against the gateway's latest state with eth_call and eth_estimateGas, without sending it.
+// The returned error describes why the transaction would fail, it is nil if the transaction would succeed.
+func simulate(ctx context.Context, client *ethclient.Client, sender common.Address, signedTx *types.Transaction) error {
+	msg := ethereum.CallMsg{
+		From:  sender,
+		To:    signedTx.To(),
+		Gas:   signedTx.Gas(),
+		Value: signedTx.Value(),
+		Data:  signedTx.Data(),
+	}
+	if signedTx.Type() == types.LegacyTxType {
+		msg.GasPrice = signedTx.GasPrice()
+	} else {
+		msg.GasFeeCap = signedTx.GasFeeCap()
+		msg.GasTipCap = signedTx.GasTipCap()
+		msg.AccessList = signedTx.AccessList()
+	}
+
+	jio.Outputln("simulating transaction with eth_call...")
+	if _, err := client.CallContract(ctx, msg, eth.LatestBlock); err != nil {
+		return errors.Wrap(revertReason(err), "eth_call failed")
+	}
+	jio.Outputln("eth_call succeeded")
+
+	jio.Outputln("estimating gas with eth_estimateGas...")
+	// The estimate is made with the node's own gas cap, so it also shows how much gas the transaction would need if its limit is too low.
+	msg.Gas = 0
+	estimate, err := client.EstimateGas(ctx, msg)
+	if err != nil {
+		return errors.Wrap(revertReason(err), "eth_estimateGas failed")
+	}
+	jio.Outputf("estimated gas: %v of the %v gas limit\n", estimate, signedTx.Gas())
+	if estimate > signedTx.Gas() {
+		return fmt.Errorf("the transaction needs an estimated %v gas but its gas limit is only %v, it would run out of gas, raise it via \"-gas-limit\"", estimate, signedTx.Gas())
+	}
+
+	return nil
+}
+
+// revertReason adds the decoded revert reason to err if the node returned one alongside it.
+func revertReason(err error) error {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return err
+	}
+	data, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err
+	}
+	b, decodeErr := hexutil.Decode(data)
+	if decodeErr != nil {
+		return err
+	}
+	reason, unpackErr := abi.UnpackRevert(b)
+	if unpackErr != nil {
+		return errors.Wrapf(err, "revert data %s", data)
+	}
+	return errors.Wrapf(err, "reverted with reason \"%s\"", reason)
+}
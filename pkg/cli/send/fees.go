@@ -0,0 +1,146 @@
+package send
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jeth/pkg/convert"
+	"github.com/Insulince/jeth/pkg/eth"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+const (
+	// baseFeeMultiplier is how many times the latest base fee the default max fee allows for, the same margin geth uses.
+	// The base fee rises by at most 12.5% per full block, so this keeps the transaction includable through several full blocks in a row.
+	baseFeeMultiplier = 2
+)
+
+// fees are the gas fees a transaction offers to pay, either a single legacy gas price or an EIP-1559 max fee and max priority fee.
+type fees struct {
+	legacy bool
+	// gasPrice is the price per unit of gas of a legacy transaction.
+	gasPrice *big.Int
+	// baseFee is the latest block's base fee, burned for every unit of gas a dynamic fee transaction uses.
+	baseFee *big.Int
+	// maxFee is the most a dynamic fee transaction pays per unit of gas, base fee and tip combined.
+	maxFee *big.Int
+	// maxPriorityFee is the most a dynamic fee transaction tips the miner per unit of gas.
+	maxPriorityFee *big.Int
+}
+
+// getFees fills in the fees cfg describes, fetching the suggested gas price, or the latest base fee and suggested tip, for any not given.
+func getFees(ctx context.Context, client *ethclient.Client, cfg Config, usdPerEth float64) (fees, error) {
+	if cfg.legacy {
+		gasPrice := big.NewInt(cfg.gasPrice)
+		if cfg.gasPrice == defaultSuggestedFee {
+			jio.Outputln("fetching suggested gas price...")
+			var err error
+			if gasPrice, err = client.SuggestGasPrice(ctx); err != nil {
+				return fees{}, errors.Wrap(err, "getting suggested gas price")
+			}
+			jio.Outputf("suggested gas price: %s wei ($%f)\n", gasPrice.String(), convert.F(convert.WeiIToUsd(gasPrice, usdPerEth)))
+		}
+		jio.Outputf("using legacy gas price: %s wei ($%f)\n", gasPrice.String(), convert.F(convert.WeiIToUsd(gasPrice, usdPerEth)))
+		return fees{legacy: true, gasPrice: gasPrice}, nil
+	}
+
+	head, err := client.HeaderByNumber(ctx, eth.LatestBlock)
+	if err != nil {
+		return fees{}, errors.Wrap(err, "fetching latest block header")
+	}
+	if head.BaseFee == nil {
+		return fees{}, errors.New("the latest block has no base fee, so this chain does not support EIP-1559 yet, please send a legacy transaction via \"-legacy\"")
+	}
+	jio.Outputf("latest base fee: %s wei ($%f)\n", head.BaseFee.String(), convert.F(convert.WeiIToUsd(head.BaseFee, usdPerEth)))
+
+	maxPriorityFee := big.NewInt(cfg.maxPriorityFee)
+	if cfg.maxPriorityFee == defaultSuggestedFee {
+		jio.Outputln("fetching suggested priority fee...")
+		if maxPriorityFee, err = client.SuggestGasTipCap(ctx); err != nil {
+			return fees{}, errors.Wrap(err, "getting suggested priority fee")
+		}
+	}
+	jio.Outputf("using max priority fee: %s wei ($%f)\n", maxPriorityFee.String(), convert.F(convert.WeiIToUsd(maxPriorityFee, usdPerEth)))
+
+	maxFee := big.NewInt(cfg.maxFee)
+	if cfg.maxFee == defaultSuggestedFee {
+		maxFee = new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(baseFeeMultiplier)), maxPriorityFee)
+	}
+	if maxFee.Cmp(maxPriorityFee) < 0 {
+		return fees{}, fmt.Errorf("max fee of %s wei must be at least the max priority fee of %s wei", maxFee, maxPriorityFee)
+	}
+	if maxFee.Cmp(head.BaseFee) < 0 {
+		jio.Outputf("WARNING: the max fee of %s wei is below the latest base fee, the transaction will wait in the mempool until the base fee falls to it\n", maxFee.String())
+	}
+	jio.Outputf("using max fee: %s wei ($%f)\n", maxFee.String(), convert.F(convert.WeiIToUsd(maxFee, usdPerEth)))
+
+	return fees{baseFee: head.BaseFee, maxFee: maxFee, maxPriorityFee: maxPriorityFee}, nil
+}
+
+// worstCase returns the most a transaction using gasLimit gas can pay in fees.
+func (f fees) worstCase(gasLimit uint64) *big.Int {
+	if f.legacy {
+		return new(big.Int).Mul(f.gasPrice, new(big.Int).SetUint64(gasLimit))
+	}
+	return new(big.Int).Mul(f.maxFee, new(big.Int).SetUint64(gasLimit))
+}
+
+// expected returns what a transaction using gasLimit gas is expected to pay in fees if it is included while the base fee is still the latest one.
+// Anything the max fee allows for beyond the base fee and tip stays with the sender.
+func (f fees) expected(gasLimit uint64) *big.Int {
+	if f.legacy {
+		return f.worstCase(gasLimit)
+	}
+
+	price := new(big.Int).Add(f.baseFee, f.maxPriorityFee)
+	if price.Cmp(f.maxFee) > 0 {
+		price = f.maxFee
+	}
+	return new(big.Int).Mul(price, new(big.Int).SetUint64(gasLimit))
+}
+
+// tx builds an unsigned transaction paying f. data is the calldata, nil for a plain ether transfer.
+func (f fees) tx(chainID *big.Int, nonce uint64, to common.Address, value *big.Int, data []byte, gasLimit uint64) *types.Transaction {
+	if f.legacy {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			To:       &to,
+			Value:    value,
+			Gas:      gasLimit,
+			GasPrice: f.gasPrice,
+			Data:     data,
+		})
+	}
+
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		To:        &to,
+		Value:     value,
+		Gas:       gasLimit,
+		GasTipCap: f.maxPriorityFee,
+		GasFeeCap: f.maxFee,
+		Data:      data,
+	})
+}
+
+// summary describes f for the summary shown before sending.
+func (f fees) summary(gasLimit uint64, usdPerEth float64) string {
+	worstCase, expected := f.worstCase(gasLimit), f.expected(gasLimit)
+	if f.legacy {
+		return fmt.Sprintf("FEE (legacy): %s wei price * %v limit = %s wei (%s ether, $%.2f)\n",
+			f.gasPrice.String(), gasLimit, worstCase.String(), convert.WeiIToEth(worstCase).String(), convert.F(convert.WeiIToUsd(worstCase, usdPerEth)),
+		)
+	}
+	return fmt.Sprintf("WORST-CASE FEE (EIP-1559): %s wei max fee * %v limit = %s wei (%s ether, $%.2f)\nEXPECTED FEE: (%s wei base fee + %s wei priority fee) * %v limit = %s wei (%s ether, $%.2f)\n",
+		f.maxFee.String(), gasLimit, worstCase.String(), convert.WeiIToEth(worstCase).String(), convert.F(convert.WeiIToUsd(worstCase, usdPerEth)),
+		f.baseFee.String(), f.maxPriorityFee.String(), gasLimit, expected.String(), convert.WeiIToEth(expected).String(), convert.F(convert.WeiIToUsd(expected, usdPerEth)),
+	)
+}
@@ -0,0 +1,140 @@
+package send
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jeth/pkg/eth"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+// gasEstimator is the part of *ethclient.Client estimateGasLimit uses.
+type gasEstimator interface {
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// gasLimit is the gas limit chosen for a transaction, along with how it was chosen for the summary.
+type gasLimit struct {
+	// limit is the gas limit the transaction is sent with.
+	limit uint64
+	// estimate is what eth_estimateGas returned, it is only set if estimated is.
+	estimate  uint64
+	estimated bool
+	// given reports whether limit was given via "-gas-limit" rather than chosen from the estimate.
+	given bool
+	// fallback reports whether limit is the latest block's gas limit, standing in for a failed estimate in a dry run.
+	fallback bool
+	// marginPercent is the safety margin added on top of the estimate, if any.
+	marginPercent uint64
+	// contract reports whether the transaction's recipient has code, which runs when it receives the transaction.
+	contract bool
+	// call reports whether the transaction calls a function of the contract, such as a token transfer, rather than only sending it ether.
+	call bool
+}
+
+// estimateGasLimit estimates the gas msg needs, then chooses the gas limit cfg describes.
+// A plain transfer to an account without code uses exactly the estimate, so it is used as is, any other estimate has cfg.gasMargin percent added on top.
+// If the estimate fails the transaction would most likely revert, which is an error unless the gas limit was given via "-gas-limit" or it is a dry run.
+// A dry run falls back to the latest block's gas limit instead, so the transaction is still signed and printed and simulate decides whether it would fail.
+func estimateGasLimit(ctx context.Context, client gasEstimator, cfg Config, msg ethereum.CallMsg) (gasLimit, error) {
+	code, err := client.CodeAt(ctx, *msg.To, eth.LatestBlock)
+	if err != nil {
+		return gasLimit{}, errors.Wrap(err, "fetching recipient's code")
+	}
+	g := gasLimit{contract: len(code) > 0, call: len(msg.Data) > 0}
+
+	jio.Outputln("estimating gas with eth_estimateGas...")
+	estimate, err := client.EstimateGas(ctx, msg)
+	if err != nil {
+		err = revertReason(err)
+		if cfg.gasLimit == estimateGas && !cfg.dryRun {
+			return gasLimit{}, errors.Wrap(err, "estimating gas, the transaction would most likely fail")
+		}
+		jio.Outputf("WARNING: could not estimate gas, the transaction would most likely fail: %v\n", err)
+		g.fallback = cfg.gasLimit == estimateGas
+	} else {
+		g.estimate, g.estimated = estimate, true
+		jio.Outputf("estimated gas: %v\n", estimate)
+	}
+
+	switch {
+	case cfg.gasLimit != estimateGas:
+		g.limit, g.given = cfg.gasLimit, true
+	case g.fallback:
+		header, err := client.HeaderByNumber(ctx, eth.LatestBlock)
+		if err != nil {
+			return gasLimit{}, errors.Wrap(err, "fetching latest block for its gas limit")
+		}
+		g.limit = header.GasLimit
+		jio.Outputln("dry run, carrying on with the latest block's gas limit in place of the estimate")
+	case g.contract:
+		g.limit, g.marginPercent = eth.WithMargin(estimate, cfg.gasMargin), cfg.gasMargin
+	default:
+		// Nothing but the transfer itself runs, so the estimate is exact and a margin would only raise the worst-case fee.
+		g.limit = estimate
+	}
+	jio.Outputf("using gas limit: %v\n", g.limit)
+
+	for _, warning := range g.warnings() {
+		jio.Outputf("WARNING: %s\n", warning)
+	}
+
+	return g, nil
+}
+
+// warnings returns anything about g which the sender should know before sending.
+func (g gasLimit) warnings() []string {
+	var warnings []string
+	if g.contract && !g.call && g.estimated && g.estimate != transferGasLimit {
+		warnings = append(warnings, fmt.Sprintf("the receiver is a contract, receiving ether runs its code, which is estimated to need %v gas rather than the %v of a plain transfer", g.estimate, transferGasLimit))
+	}
+	if g.estimated && g.limit < g.estimate {
+		warnings = append(warnings, fmt.Sprintf("the gas limit of %v is below the estimate of %v, the transaction will most likely run out of gas", g.limit, g.estimate))
+	}
+	return warnings
+}
+
+// summary describes g for the summary shown before sending.
+func (g gasLimit) summary() string {
+	var how string
+	switch {
+	case g.given && g.estimated:
+		how = fmt.Sprintf("given via \"-gas-limit\", estimated %v", g.estimate)
+	case g.given:
+		how = "given via \"-gas-limit\", could not be estimated"
+	case g.fallback:
+		how = "the latest block's gas limit, could not be estimated, the transaction would most likely fail"
+	case g.contract:
+		how = fmt.Sprintf("estimated %v + %v%% margin", g.estimate, g.marginPercent)
+	default:
+		how = "estimated, exact for a plain transfer"
+	}
+
+	s := fmt.Sprintf("GAS LIMIT: %v (%s)\n", g.limit, how)
+	for _, warning := range g.warnings() {
+		s += fmt.Sprintf("WARNING: %s\n", warning)
+	}
+	return s
+}
+
+// checkBalance panics with err, the result of checking the sender's balance covers the transaction with txGasLimit, unless it is nil.
+// The latest block's gas limit a dry run falls back to is rarely affordable, yet the transaction would fail regardless,
+// so then err is only a warning, leaving simulate to decide.
+func checkBalance(cfg Config, txGasLimit gasLimit, err error) {
+	if err == nil {
+		return
+	}
+	if !txGasLimit.fallback {
+		panic(errors.Wrap(err, "checking sender's balance"))
+	}
+	jio.Outputf("WARNING: checking sender's balance against the fallback gas limit: %v\n", err)
+}
@@ -0,0 +1,111 @@
+package send
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEstimator estimates estimate gas for every message, or fails with err, for a recipient with code.
+type fakeEstimator struct {
+	code     []byte
+	estimate uint64
+	err      error
+}
+
+func (f fakeEstimator) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return f.code, nil
+}
+
+func (f fakeEstimator) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+	return f.estimate, f.err
+}
+
+func (f fakeEstimator) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	return &types.Header{GasLimit: 30000000}, nil
+}
+
+func Test_estimateGasLimit(t *testing.T) {
+	reverted := errors.New("execution reverted")
+	contract := []byte{0x60, 0x80}
+
+	tests := []struct {
+		name      string
+		estimator fakeEstimator
+		cfg       Config
+		expected  gasLimit
+		err       bool
+	}{
+		{
+			name:      "plain transfer",
+			estimator: fakeEstimator{estimate: transferGasLimit},
+			cfg:       Config{gasLimit: estimateGas, gasMargin: 20},
+			expected:  gasLimit{limit: transferGasLimit, estimate: transferGasLimit, estimated: true},
+		},
+		{
+			name:      "contract",
+			estimator: fakeEstimator{code: contract, estimate: 50000},
+			cfg:       Config{gasLimit: estimateGas, gasMargin: 20},
+			expected:  gasLimit{limit: 60000, estimate: 50000, estimated: true, marginPercent: 20, contract: true},
+		},
+		{
+			name:      "given",
+			estimator: fakeEstimator{code: contract, estimate: 50000},
+			cfg:       Config{gasLimit: 100000, gasMargin: 20},
+			expected:  gasLimit{limit: 100000, estimate: 50000, estimated: true, given: true, contract: true},
+		},
+		{
+			name:      "reverts",
+			estimator: fakeEstimator{code: contract, err: reverted},
+			cfg:       Config{gasLimit: estimateGas, gasMargin: 20},
+			err:       true,
+		},
+		{
+			name:      "reverts, given",
+			estimator: fakeEstimator{code: contract, err: reverted},
+			cfg:       Config{gasLimit: 100000, gasMargin: 20},
+			expected:  gasLimit{limit: 100000, given: true, contract: true},
+		},
+		{
+			name:      "reverts, dry run falls back to the latest block's gas limit",
+			estimator: fakeEstimator{code: contract, err: reverted},
+			cfg:       Config{gasLimit: estimateGas, gasMargin: 20, dryRun: true},
+			expected:  gasLimit{limit: 30000000, fallback: true, contract: true},
+		},
+		{
+			name:      "reverts, dry run, given",
+			estimator: fakeEstimator{code: contract, err: reverted},
+			cfg:       Config{gasLimit: 100000, gasMargin: 20, dryRun: true},
+			expected:  gasLimit{limit: 100000, given: true, contract: true},
+		},
+	}
+
+	to := common.HexToAddress("0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g, err := estimateGasLimit(context.Background(), test.estimator, test.cfg, ethereum.CallMsg{To: &to})
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, g)
+		})
+	}
+}
+
+func Test_checkBalance(t *testing.T) {
+	insufficient := errors.New("insufficient funds")
+
+	assert.NotPanics(t, func() { checkBalance(Config{}, gasLimit{}, nil) })
+	assert.Panics(t, func() { checkBalance(Config{}, gasLimit{}, insufficient) })
+	assert.Panics(t, func() { checkBalance(Config{dryRun: true}, gasLimit{given: true}, insufficient) })
+	assert.NotPanics(t, func() { checkBalance(Config{dryRun: true}, gasLimit{fallback: true}, insufficient) })
+}
@@ -0,0 +1,271 @@
+package send
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/eth"
+	"github.com/Insulince/jeth/pkg/nft"
+	"github.com/Insulince/jeth/pkg/store"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+// NFTCommand is "jeth send-nft". It shares send's signers, fees, gas limit, summary, and confirmation.
+var NFTCommand = &cli.Command{
+	Name:    "send-nft",
+	Summary: "send ERC-721 or ERC-1155 NFTs to a wallet",
+	Args:    "[flags]",
+	Description: `
+Sends NFTs of -contract from the signer's wallet to -receiver-address with safeTransferFrom, as an EIP-1559 transaction, or a legacy one with -legacy.
+Whether -contract is ERC-721 or ERC-1155 is detected with ERC-165 supportsInterface. An ERC-721 transfer sends a single -id, which the sender must own.
+An ERC-1155 transfer sends -amount of each -id, 1 of each if -amount is not given, several ids at once with safeBatchTransferFrom, and the sender must hold them.
+The fee is paid in ether. A summary is shown and must be confirmed before anything is sent, with the same signers and fee flags as send.`,
+	Examples: []string{
+		"send-nft -keystore @main -receiver-address @vault -contract 0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D -id 42",
+		"send-nft -keystore @main -receiver-address @friend -contract @items -id 7 -amount 3",
+		"send-nft -keystore @main -receiver-address @vault -contract @items -id 1 -amount 10 -id 2 -amount 1",
+		"send-nft -keystore @main -receiver-address @vault -contract @items -id 1 -dry-run",
+	},
+	ExitCodes: map[int]string{
+		cli.ExitRejected: "with -dry-run, the transaction would fail",
+	},
+	Run: runNFT,
+}
+
+// nftConfig is what send-nft sends, alongside the Config it shares with send.
+type nftConfig struct {
+	contract wallet.Address
+	ids      []*big.Int
+	// amounts are the amounts of each of ids to send, nil if "-amount" was not given.
+	amounts []*big.Int
+}
+
+func getNFTConfig(env *cli.Env, args []string) (cfg Config, nc nftConfig, err error) {
+	var senderRef string
+	var receiverRef string
+	var contractRef string
+	var ids cli.StringsFlag
+	var amounts cli.StringsFlag
+
+	registerSignerFlags(env, &cfg, &senderRef)
+	env.Flags.StringVar(&receiverRef, "receiver-address", "", "the receiver's wallet address, or \"@name\" of a wallet or contact in the store, mixed case addresses must have a valid EIP-55 checksum [required via flag or stdin at runtime]")
+	env.Flags.StringVar(&contractRef, "contract", "", "the address of the ERC-721 or ERC-1155 contract, or \"@name\" of a contact in the store [required]")
+	env.Flags.Var(&ids, "id", "a token id to send, in decimal or 0x prefixed hexadecimal, may be given more than once for an ERC-1155 batch transfer [required]")
+	env.Flags.Var(&amounts, "amount", "for ERC-1155, the amount of the token id given by the matching \"-id\" to send, given once per \"-id\" in the same order, or left out to send 1 of each")
+	registerTxFlags(env, &cfg)
+	env.Parse(args)
+
+	cfg.addressBook, err = store.OpenDefault()
+	if err != nil {
+		return Config{}, nftConfig{}, errors.Wrap(err, "opening store")
+	}
+
+	if err := checkSigner(&cfg, senderRef); err != nil {
+		return Config{}, nftConfig{}, err
+	}
+	if err := resolveReceiver(&cfg, receiverRef); err != nil {
+		return Config{}, nftConfig{}, err
+	}
+	if contractRef == "" {
+		return Config{}, nftConfig{}, errors.New("must provide the address of the NFT contract via \"-contract\"")
+	}
+	nc.contract, _, err = cfg.addressBook.Resolve(contractRef)
+	if err != nil {
+		return Config{}, nftConfig{}, errors.Wrap(err, "resolving contract's address")
+	}
+	if nc.contract.IsZero() {
+		return Config{}, nftConfig{}, errors.New("must provide the address of an NFT contract other than the zero address via \"-contract\"")
+	}
+	if nc.contract == cfg.receiverWalletAddress {
+		return Config{}, nftConfig{}, errors.New("the receiver is the NFT contract itself, NFTs sent to it are most likely lost, provide the receiver's own wallet address via \"-receiver-address\"")
+	}
+	if nc.ids, err = parseTokenIDs(ids); err != nil {
+		return Config{}, nftConfig{}, err
+	}
+	if len(amounts) > 0 {
+		if len(amounts) != len(ids) {
+			return Config{}, nftConfig{}, fmt.Errorf("must provide \"-amount\" once for every \"-id\", got %v token ids and %v amounts", len(ids), len(amounts))
+		}
+		if nc.amounts, err = parseNFTAmounts(amounts); err != nil {
+			return Config{}, nftConfig{}, err
+		}
+	}
+	if err := checkTxFlags(cfg); err != nil {
+		return Config{}, nftConfig{}, err
+	}
+	obfuscatedPrivateKeyHex := ""
+	if cfg.privateKeyHex != "" {
+		obfuscatedPrivateKeyHex = eth.ObfuscateKey(cfg.privateKeyHex)
+	}
+	jio.Outputf("configuration parsed successfully (private key obfuscated):\n\t-signer=%s\n\t-private-key=%s\n\t-keystore=%s\n\t-remote-signer-url=%s\n\t-sender-address=%s\n\t-receiver-address=%s\n\t-contract=%s\n\t-id=%s\n\t-amount=%s\n\t-legacy=%v\n\t-gas-price=%v\n\t-max-fee=%v\n\t-max-priority-fee=%v\n\t-gas-limit=%v\n\t-gas-margin=%v\n\t-dry-run=%v\n\t-gateway=%s\n\t-chain=%s\n", cfg.signerKind, obfuscatedPrivateKeyHex, cfg.keystorePath, cfg.remoteSignerURL, senderRef, cfg.addressBook.Display(cfg.receiverWalletAddress), cfg.addressBook.Display(nc.contract), ids.String(), amounts.String(), cfg.legacy, cfg.gasPrice, cfg.maxFee, cfg.maxPriorityFee, cfg.gasLimit, cfg.gasMargin, cfg.dryRun, env.Gateway, env.Chain.Name)
+
+	return cfg, nc, nil
+}
+
+func runNFT(ctx context.Context, env *cli.Env, args []string) {
+	cfg, nc, err := getNFTConfig(env, args)
+	if err != nil {
+		panic(errors.Wrap(err, "getting config"))
+	}
+
+	jio.Outputf("send-nft initiated at %v\n", time.Now().Format(time.RFC3339Nano))
+	defer func() { jio.Outputf("send-nft completed at %v\n", time.Now().Format(time.RFC3339Nano)) }()
+
+	s := &session{}
+	defer s.close()
+	s.open(ctx, env, &cfg)
+
+	standard, err := nft.Detect(ctx, s.client, nc.contract.Common())
+	if err != nil {
+		panic(errors.Wrapf(err, "detecting the standard of contract \"%s\"", nc.contract))
+	}
+	jio.Outputf("contract: %s implements %s\n", cfg.addressBook.Display(nc.contract), standard)
+
+	var txData []byte
+	var held []*big.Int
+	switch standard {
+	case nft.ERC721:
+		txData, err = nftERC721Transfer(ctx, s, cfg, nc)
+	case nft.ERC1155:
+		txData, held, err = nftERC1155Transfer(ctx, s, cfg, &nc)
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	txTo := nc.contract.Common()
+	txFees, txGasLimit := s.gas(ctx, cfg, txTo, new(big.Int), txData)
+	checkBalance(cfg, txGasLimit, coversFee(s.balance, txFees.worstCase(txGasLimit.limit)))
+
+	jio.Outputf("will send to wallet address: %s\n", cfg.addressBook.Display(cfg.receiverWalletAddress))
+
+	jio.SilentOutputln("")
+	summary := summarizeNFT(standard, nc, held, s.balance, txFees, txGasLimit, cfg.addressBook.Display(nc.contract), cfg.addressBook.Display(s.sender), cfg.addressBook.Display(cfg.receiverWalletAddress), s.usdPerEth)
+	s.submit(ctx, env, cfg, summary, txFees, txTo, new(big.Int), txData, txGasLimit.limit)
+}
+
+// nftERC721Transfer checks that the sender owns the token id nc sends, then encodes its transfer.
+func nftERC721Transfer(ctx context.Context, s *session, cfg Config, nc nftConfig) ([]byte, error) {
+	if len(nc.ids) != 1 {
+		return nil, fmt.Errorf("an %s transfer sends a single token id, got %v, send each separately", nft.ERC721, len(nc.ids))
+	}
+	if nc.amounts != nil && nc.amounts[0].Cmp(big.NewInt(1)) != 0 {
+		return nil, fmt.Errorf("an %s token id is unique, \"-amount\" must be 1 or left out, got %s", nft.ERC721, nc.amounts[0])
+	}
+	id := nc.ids[0]
+
+	owner, err := nft.OwnerOf(ctx, s.client, nc.contract.Common(), id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching owner of token id %s", id)
+	}
+	if owner != s.sender.Common() {
+		return nil, fmt.Errorf("token id %s is owned by %s, not the sender %s", id, cfg.addressBook.Display(wallet.Address(owner)), cfg.addressBook.Display(s.sender))
+	}
+	jio.Outputf("sender owns token id %s\n", id)
+
+	data, err := nft.EncodeERC721Transfer(s.sender.Common(), cfg.receiverWalletAddress.Common(), id)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding transfer")
+	}
+	return data, nil
+}
+
+// nftERC1155Transfer checks that the sender holds the amount of each token id nc sends, 1 of each if no amounts were given, then encodes their transfer.
+// It returns the sender's balance of each token id alongside.
+func nftERC1155Transfer(ctx context.Context, s *session, cfg Config, nc *nftConfig) ([]byte, []*big.Int, error) {
+	if nc.amounts == nil {
+		nc.amounts = make([]*big.Int, len(nc.ids))
+		for i := range nc.amounts {
+			nc.amounts[i] = big.NewInt(1)
+		}
+	}
+
+	balances, err := nft.BalanceOfBatch(ctx, s.client, nc.contract.Common(), s.sender.Common(), nc.ids)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "fetching sender's balances")
+	}
+	for i, id := range nc.ids {
+		if balances[i].Cmp(nc.amounts[i]) < 0 {
+			return nil, nil, errors.Wrapf(eth.ErrInsufficientFunds, "sender holds %s of token id %s, cannot send %s", balances[i], id, nc.amounts[i])
+		}
+		jio.Outputf("sender holds %s of token id %s, sending %s\n", balances[i], id, nc.amounts[i])
+	}
+
+	data, err := nft.EncodeERC1155Transfer(s.sender.Common(), cfg.receiverWalletAddress.Common(), nc.ids, nc.amounts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "encoding transfer")
+	}
+	return data, balances, nil
+}
+
+// parseTokenIDs parses each of ids, as given via "-id", in decimal or 0x prefixed hexadecimal. Each token id may only be given once.
+func parseTokenIDs(ids []string) ([]*big.Int, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("must provide at least one token id to send via \"-id\"")
+	}
+
+	parsed := make([]*big.Int, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for i, s := range ids {
+		id, err := nft.ParseTokenID(s)
+		if err != nil {
+			return nil, err
+		}
+		if seen[id.String()] {
+			return nil, fmt.Errorf("token id %s is given more than once via \"-id\", give its total amount once instead", id)
+		}
+		seen[id.String()] = true
+		parsed[i] = id
+	}
+	return parsed, nil
+}
+
+// parseNFTAmounts parses each of amounts, as given via "-amount", as a positive integer.
+func parseNFTAmounts(amounts []string) ([]*big.Int, error) {
+	parsed := make([]*big.Int, len(amounts))
+	for i, s := range amounts {
+		amount, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+		if !ok || amount.Sign() <= 0 {
+			return nil, fmt.Errorf("amount \"%s\" is not a positive integer, NFT amounts are whole", s)
+		}
+		parsed[i] = amount
+	}
+	return parsed, nil
+}
+
+// summarizeNFT describes the transfer of the NFTs nc sends, for the summary shown before sending. held is the sender's balance of each of nc's token ids for ERC-1155, balance is the sender's ether balance.
+func summarizeNFT(standard nft.Standard, nc nftConfig, held []*big.Int, balance *big.Int, txFees fees, txGasLimit gasLimit, contractAddress, senderWalletAddress, receiverWalletAddress string, usdPerEth float64) string {
+	worstCase := txFees.worstCase(txGasLimit.limit)
+
+	var sending string
+	switch {
+	case standard == nft.ERC721:
+		sending = fmt.Sprintf("NFT SENDING (%s): token id %s\n", standard, nc.ids[0])
+	case len(nc.ids) == 1:
+		sending = fmt.Sprintf("NFT SENDING (%s): %s of token id %s, %s held\n", standard, nc.amounts[0], nc.ids[0], held[0])
+	default:
+		sending = fmt.Sprintf("NFT SENDING (%s, batch of %v token ids):\n", standard, len(nc.ids))
+		for i, id := range nc.ids {
+			sending += fmt.Sprintf("\t%s of token id %s, %s held\n", nc.amounts[i], id, held[i])
+		}
+	}
+
+	return fmt.Sprintf("%sCONTRACT:\t%s\n%s%sWORST-CASE TOTAL COST: %s\nETHER BALANCE: %s, at least %s left after\nFROM:\t%s\nTO:\t%s\n",
+		sending,
+		contractAddress,
+		txGasLimit.summary(),
+		txFees.summary(txGasLimit.limit, usdPerEth),
+		ether(worstCase, usdPerEth),
+		ether(balance, usdPerEth), ether(new(big.Int).Sub(balance, worstCase), usdPerEth),
+		senderWalletAddress,
+		receiverWalletAddress,
+	)
+}
@@ -0,0 +1,267 @@
+package send
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/convert"
+	"github.com/Insulince/jeth/pkg/erc20"
+	"github.com/Insulince/jeth/pkg/eth"
+	"github.com/Insulince/jeth/pkg/store"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+const (
+	// transferGasLimit is the exact gas a plain ether transfer to an account without code uses.
+	transferGasLimit    = uint64(21000)
+	defaultSuggestedFee = 0
+	// estimateGas means -gas-limit was not given, so it is estimated with eth_estimateGas.
+	estimateGas      = 0
+	defaultGasMargin = 20
+
+	signerPrivateKey = "private-key"
+	signerKeystore   = "keystore"
+	signerRemote     = "remote"
+)
+
+// Command is "jeth send".
+var Command = &cli.Command{
+	Name:    "send",
+	Summary: "send ether or an ERC-20 token to a wallet",
+	Args:    "[flags]",
+	Description: `
+Sends ether from the signer's wallet to -receiver-address as an EIP-1559 transaction, or a legacy one with -legacy.
+By default the worst-case fee is taken out of -amount, so the sender never spends more than it. With "-fee-mode on-top" the receiver gets exactly -amount
+and the fee is paid on top, and "-amount max" sends the whole balance minus the worst-case fee. A summary is shown and must be confirmed before anything is sent.
+With -token an ERC-20 token is sent instead, -amount is then in whole tokens and "max" sends the whole token balance. A token transfer's fee is
+always paid on top, in ether, and only well-known tokens are priced in dollars, by address, as a token contract can claim any symbol.
+The transaction is signed with a private key, a keystore file, or a Clef-compatible remote signer, see -signer.`,
+	Examples: []string{
+		"send -keystore @main -receiver-address @savings -amount 0.5",
+		"send -keystore ./keystore.json -receiver-address 0x19325d2D5c17AF1096D28A12850D27bD182612F6 -amount 0.1 -max-priority-fee 2000000000",
+		"send -keystore @main -receiver-address @vendor -amount 0.25 -fee-mode on-top",
+		"send -keystore @old -receiver-address @savings -amount max",
+		"send -keystore @main -receiver-address @vendor -token 0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48 -amount 250",
+		"send -keystore @main -receiver-address @savings -token @usdc -amount max",
+		"send -signer remote -receiver-address @savings -amount 1",
+		"send -keystore @main -receiver-address @savings -amount 0.5 -dry-run",
+	},
+	ExitCodes: map[int]string{
+		cli.ExitRejected: "with -dry-run, the transaction would fail",
+	},
+	Run: run,
+}
+
+type (
+	Config struct {
+		signerKind            string
+		privateKeyHex         string
+		keystorePath          string
+		remoteSignerURL       string
+		senderWalletAddress   wallet.Address
+		receiverWalletAddress wallet.Address
+		token                 wallet.Address
+		amountText            string
+		amount                *big.Int
+		sendMax               bool
+		feeMode               string
+		legacy                bool
+		gasPrice              int64
+		maxFee                int64
+		maxPriorityFee        int64
+		gasLimit              uint64
+		gasMargin             uint64
+		dryRun                bool
+		addressBook           *store.Store
+	}
+)
+
+func getConfig(env *cli.Env, args []string) (cfg Config, err error) {
+	var senderRef string
+	var receiverRef string
+	var tokenRef string
+	var amount string
+
+	registerSignerFlags(env, &cfg, &senderRef)
+	env.Flags.StringVar(&receiverRef, "receiver-address", "", "the receiver's wallet address, or \"@name\" of a wallet or contact in the store, mixed case addresses must have a valid EIP-55 checksum [required via flag or stdin at runtime]")
+	env.Flags.StringVar(&tokenRef, "token", "", "the address of an ERC-20 token contract, or \"@name\" of a contact in the store, to send that token instead of ether")
+	env.Flags.StringVar(&amount, "amount", "", fmt.Sprintf("the amount to send in ether units, or in whole tokens with \"-token\", or \"%s\" to send the whole balance, of ether minus the worst-case fee or of the token [required via flag or stdin at runtime]", amountMax))
+	env.Flags.StringVar(&cfg.feeMode, "fee-mode", "", fmt.Sprintf("how the fee is paid, \"%s\" (the default) takes it out of the amount so the receiver gets less, \"%s\" pays it on top so the receiver gets exactly the amount, a token transfer always pays it on top", feeModeDeduct, feeModeOnTop))
+	registerTxFlags(env, &cfg)
+	env.Parse(args)
+
+	cfg.addressBook, err = store.OpenDefault()
+	if err != nil {
+		return Config{}, errors.Wrap(err, "opening store")
+	}
+
+	if err := checkSigner(&cfg, senderRef); err != nil {
+		return Config{}, err
+	}
+	if err := resolveReceiver(&cfg, receiverRef); err != nil {
+		return Config{}, err
+	}
+	if tokenRef != "" {
+		cfg.token, _, err = cfg.addressBook.Resolve(tokenRef)
+		if err != nil {
+			return Config{}, errors.Wrap(err, "resolving token's address")
+		}
+		if cfg.token.IsZero() {
+			return Config{}, errors.New("must provide the address of an ERC-20 token contract other than the zero address via \"-token\"")
+		}
+		if cfg.token == cfg.receiverWalletAddress {
+			return Config{}, errors.New("the receiver is the token contract itself, tokens sent to it are most likely lost, provide the receiver's own wallet address via \"-receiver-address\"")
+		}
+	}
+	if amount == "" {
+		unit := "ether"
+		if !cfg.token.IsZero() {
+			unit = "token"
+		}
+		amount = jio.MustInputWithPrompt(fmt.Sprintf("sender's %s amount to send not given via \"-amount\" flag, enter manually (or \"%s\") instead: ", unit, amountMax))
+	}
+	if err := parseAmount(&cfg, amount); err != nil {
+		return Config{}, errors.Wrap(err, "parsing amount")
+	}
+	if cfg.feeMode == "" {
+		cfg.feeMode = feeModeDeduct
+		if !cfg.token.IsZero() {
+			cfg.feeMode = feeModeOnTop
+		}
+	}
+	if cfg.feeMode != feeModeDeduct && cfg.feeMode != feeModeOnTop {
+		return Config{}, fmt.Errorf("unknown fee mode \"%s\", must provide \"%s\" or \"%s\" via \"-fee-mode\"", cfg.feeMode, feeModeDeduct, feeModeOnTop)
+	}
+	if !cfg.token.IsZero() && cfg.feeMode != feeModeOnTop {
+		return Config{}, fmt.Errorf("a token transfer's fee is paid in ether so it cannot be taken out of the amount, \"-fee-mode %s\" cannot be used with \"-token\"", cfg.feeMode)
+	}
+	if cfg.token.IsZero() && cfg.sendMax && cfg.feeMode == feeModeOnTop {
+		return Config{}, fmt.Errorf("\"-amount %s\" always takes the fee out of the balance, it cannot be combined with \"-fee-mode %s\"", amountMax, feeModeOnTop)
+	}
+	if err := checkTxFlags(cfg); err != nil {
+		return Config{}, err
+	}
+	obfuscatedPrivateKeyHex := ""
+	if cfg.privateKeyHex != "" {
+		obfuscatedPrivateKeyHex = eth.ObfuscateKey(cfg.privateKeyHex)
+	}
+	jio.Outputf("configuration parsed successfully (private key obfuscated):\n\t-signer=%s\n\t-private-key=%s\n\t-keystore=%s\n\t-remote-signer-url=%s\n\t-sender-address=%s\n\t-receiver-address=%s\n\t-token=%s\n\t-amount=%s\n\t-fee-mode=%s\n\t-legacy=%v\n\t-gas-price=%v\n\t-max-fee=%v\n\t-max-priority-fee=%v\n\t-gas-limit=%v\n\t-gas-margin=%v\n\t-dry-run=%v\n\t-gateway=%s\n\t-chain=%s\n", cfg.signerKind, obfuscatedPrivateKeyHex, cfg.keystorePath, cfg.remoteSignerURL, senderRef, cfg.addressBook.Display(cfg.receiverWalletAddress), tokenRef, amount, cfg.feeMode, cfg.legacy, cfg.gasPrice, cfg.maxFee, cfg.maxPriorityFee, cfg.gasLimit, cfg.gasMargin, cfg.dryRun, env.Gateway, env.Chain.Name)
+
+	return cfg, nil
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	cfg, err := getConfig(env, args)
+	if err != nil {
+		panic(errors.Wrap(err, "getting config"))
+	}
+
+	jio.Outputf("send initiated at %v\n", time.Now().Format(time.RFC3339Nano))
+	defer func() { jio.Outputf("send completed at %v\n", time.Now().Format(time.RFC3339Nano)) }()
+
+	s := &session{}
+	defer s.close()
+	s.open(ctx, env, &cfg)
+
+	toAddress := cfg.receiverWalletAddress.Common()
+	// A token transfer sends no ether, it calls the token's transfer function instead.
+	txTo, txData := toAddress, []byte(nil)
+	var tok token
+	var bTokenAmount *big.Int
+
+	// When sending the whole balance the value is only known once the fee is, the balance stands in for it until then.
+	bWei := s.balance
+	if !cfg.token.IsZero() {
+		tok, err = getToken(ctx, s.client, env.Chain.ID, cfg.token, s.sender)
+		if err != nil {
+			panic(errors.Wrapf(err, "reading token \"%s\"", cfg.token))
+		}
+		jio.Outputf("token: %s (%s, %v decimals)\n", cfg.addressBook.Display(cfg.token), tok.symbol, tok.decimals)
+		jio.Outputf("sender's token balance: %s\n", tok.format(tok.balance))
+
+		bTokenAmount, err = tok.amount(cfg)
+		if err != nil {
+			panic(errors.Wrap(err, "checking sender's token balance"))
+		}
+		jio.Outputf("tokens to be sent: %s\n", tok.format(bTokenAmount))
+
+		txData, err = erc20.EncodeTransfer(toAddress, bTokenAmount)
+		if err != nil {
+			panic(errors.Wrap(err, "encoding token transfer"))
+		}
+		txTo, bWei = cfg.token.Common(), new(big.Int)
+	} else if cfg.sendMax {
+		jio.Outputln("ether to be sent: the whole balance minus the worst-case fee")
+	} else {
+		bWei = cfg.amount
+		jio.Outputf("ether to be sent: %s\n", ether(bWei, s.usdPerEth))
+		jio.Outputf("equivalent wei to be sent: %s wei ($%.2f)\n", bWei.String(), convert.F(convert.WeiIToUsd(bWei, s.usdPerEth)))
+	}
+
+	txFees, txGasLimit := s.gas(ctx, cfg, txTo, bWei, txData)
+	bTotalGas := txFees.worstCase(txGasLimit.limit)
+
+	// Whichever the fee mode, the worst-case fee is what is checked against the balance, so however the base fee moves the transaction can be paid for.
+	var bValue *big.Int
+	if cfg.token.IsZero() {
+		if bValue, err = sendValue(cfg, bWei, s.balance, bTotalGas); err != nil {
+			checkBalance(cfg, txGasLimit, err)
+			bValue = bWei
+		}
+		jio.Outputf("wei the receiver will get (fee mode: %s, amount: %s): %s wei ($%.2f)\n", cfg.feeMode, amountDescription(cfg), bValue.String(), convert.F(convert.WeiIToUsd(bValue, s.usdPerEth)))
+	} else {
+		checkBalance(cfg, txGasLimit, coversFee(s.balance, bTotalGas))
+		bValue = bWei
+	}
+
+	jio.Outputf("will send to wallet address: %s\n", cfg.addressBook.Display(cfg.receiverWalletAddress))
+
+	jio.SilentOutputln("")
+	var summary string
+	if cfg.token.IsZero() {
+		summary = summarize(cfg, bWei, bValue, s.balance, txFees, txGasLimit, cfg.addressBook.Display(s.sender), cfg.addressBook.Display(cfg.receiverWalletAddress), s.usdPerEth)
+	} else {
+		summary = summarizeToken(tok, bTokenAmount, s.balance, txFees, txGasLimit, cfg.addressBook.Display(cfg.token), cfg.addressBook.Display(s.sender), cfg.addressBook.Display(cfg.receiverWalletAddress), s.usdPerEth)
+	}
+	s.submit(ctx, env, cfg, summary, txFees, txTo, bValue, txData, txGasLimit.limit)
+}
+
+// summarize describes the transaction sending value wei, for amount wei as given via "-amount", for the summary shown before sending.
+func summarize(cfg Config, amount, value, balance *big.Int, txFees fees, txGasLimit gasLimit, senderWalletAddress, receiverWalletAddress string, usdPerEth float64) string {
+	worstCase := txFees.worstCase(txGasLimit.limit)
+
+	var sending string
+	switch {
+	case cfg.sendMax:
+		sending = fmt.Sprintf("AMOUNT SENDING (the whole balance minus the worst-case fee): %s\n", ether(value, usdPerEth))
+		if !txFees.legacy {
+			sending += "NOTE: whatever of the worst-case fee is not charged stays behind in the sender's wallet, send with \"-legacy\" to a plain account to leave nothing behind\n"
+		}
+	case cfg.feeMode == feeModeOnTop:
+		sending = fmt.Sprintf("AMOUNT SENDING (exact, the fee is paid on top): %s\n", ether(value, usdPerEth))
+	default:
+		gasProportion := new(big.Float).Quo(new(big.Float).SetInt(worstCase), new(big.Float).SetInt(amount))
+		sending = fmt.Sprintf("ORIGINAL AMOUNT SENDING: %s\nGAS ADJUSTED AMOUNT SENDING (the fee is taken out of it): %s [↓ %.3f%%]\n",
+			ether(amount, usdPerEth),
+			ether(value, usdPerEth), convert.F(gasProportion)*100,
+		)
+	}
+
+	spent := new(big.Int).Add(value, worstCase)
+	return fmt.Sprintf("%s%s%sWORST-CASE TOTAL COST: %s\nBALANCE: %s, at least %s left after\nFROM:\t%s\nTO:\t%s\n",
+		sending,
+		txGasLimit.summary(),
+		txFees.summary(txGasLimit.limit, usdPerEth),
+		ether(spent, usdPerEth),
+		ether(balance, usdPerEth), ether(new(big.Int).Sub(balance, spent), usdPerEth),
+		senderWalletAddress,
+		receiverWalletAddress,
+	)
+}
@@ -0,0 +1,181 @@
+package send
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/convert"
+	"github.com/Insulince/jeth/pkg/eth"
+	"github.com/Insulince/jeth/pkg/price"
+	"github.com/Insulince/jeth/pkg/signer"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+// session is what sending any transaction needs once the config is parsed: the gateway, the signer, and the sender's nonce and balance.
+type session struct {
+	client *ethclient.Client
+	signer signer.Signer
+	sender wallet.Address
+	nonce  uint64
+	// balance is the sender's ether balance.
+	balance   *big.Int
+	usdPerEth float64
+}
+
+// open fetches the ether price, connects to the gateway, creates the signer cfg describes, and fetches the sender's nonce and balance, panicking on any error.
+// cfg's private key is cleared once the signer holds it. s must be closed however open returns, panics included.
+func (s *session) open(ctx context.Context, env *cli.Env, cfg *Config) {
+	var err error
+	s.usdPerEth, err = price.UsdPerEth()
+	if err != nil {
+		panic(errors.Wrap(err, "fetching latest eth price"))
+	}
+	jio.Outputf("current usd per ether (this figure will be used in later approximations): $%v\n", s.usdPerEth)
+
+	s.client, err = env.Dial(ctx)
+	if err != nil {
+		panic(err)
+	}
+	jio.Outputf("connected to gateway: %s (%s)\n", env.Gateway, env.Chain.Describe())
+
+	s.signer, err = getSigner(ctx, *cfg)
+	if err != nil {
+		panic(errors.Wrap(err, "getting signer"))
+	}
+	cfg.privateKeyHex = ""
+	jio.Outputf("using %s signer\n", cfg.signerKind)
+
+	s.sender, err = wallet.ParseAddress(s.signer.Address())
+	if err != nil {
+		panic(errors.Wrap(err, "parsing signer's address"))
+	}
+	jio.Outputf("sender's wallet address extracted from signer: [WALLET] %s\n", cfg.addressBook.Display(s.sender))
+
+	s.nonce, err = s.client.PendingNonceAt(ctx, s.sender.Common())
+	if err != nil {
+		panic(errors.Wrapf(err, "fetching latest pending nonce for sender's wallet \"%s\"", s.sender))
+	}
+	jio.Outputf("sender's nonce extracted from wallet address: [NONCE] %v\n", s.nonce)
+
+	s.balance, err = s.client.BalanceAt(ctx, s.sender.Common(), eth.LatestBlock)
+	if err != nil {
+		panic(errors.Wrapf(err, "fetching balance of sender's wallet \"%s\"", s.sender))
+	}
+	jio.Outputf("sender's balance: %s wei ($%.2f)\n", s.balance.String(), convert.F(convert.WeiIToUsd(s.balance, s.usdPerEth)))
+}
+
+// close wipes any key material the signer holds, closes its connection to a remote signer, and disconnects from the gateway. The signer is wiped sooner still once the transaction is signed.
+func (s *session) close() {
+	if s.signer != nil {
+		cli.DestroySigner(s.signer)
+		cli.CloseSigner(s.signer)
+	}
+	if s.client != nil {
+		s.client.Close()
+	}
+}
+
+// gas chooses the fees and gas limit of the transaction sending value wei and data to to, panicking on any error.
+func (s *session) gas(ctx context.Context, cfg Config, to common.Address, value *big.Int, data []byte) (fees, gasLimit) {
+	txFees, err := getFees(ctx, s.client, cfg, s.usdPerEth)
+	if err != nil {
+		panic(errors.Wrap(err, "getting fees"))
+	}
+
+	txGasLimit, err := estimateGasLimit(ctx, s.client, cfg, ethereum.CallMsg{
+		From:  s.sender.Common(),
+		To:    &to,
+		Value: value,
+		Data:  data,
+	})
+	if err != nil {
+		panic(errors.Wrap(err, "choosing gas limit"))
+	}
+
+	bTotalGas := txFees.worstCase(txGasLimit.limit)
+	jio.Outputf("worst-case total gas for this transaction: %s wei ($%.2f)\n", bTotalGas.String(), convert.F(convert.WeiIToUsd(bTotalGas, s.usdPerEth)))
+	bExpectedGas := txFees.expected(txGasLimit.limit)
+	jio.Outputf("expected total gas for this transaction: %s wei ($%.2f)\n", bExpectedGas.String(), convert.F(convert.WeiIToUsd(bExpectedGas, s.usdPerEth)))
+
+	return txFees, txGasLimit
+}
+
+// submit shows summary and asks for confirmation, then builds, signs, and sends the transaction sending value wei and data to to, paying txFees with gasLimit.
+// With "-dry-run" it is simulated instead of sent, exiting with cli.ExitRejected if it would fail.
+func (s *session) submit(ctx context.Context, env *cli.Env, cfg Config, summary string, txFees fees, to common.Address, value *big.Int, data []byte, gasLimit uint64) {
+	jio.Outputln("----- SUMMARY -----")
+	jio.SilentOutputln(summary)
+
+	if cfg.dryRun {
+		// Nothing is sent in a dry run, so there is nothing to confirm.
+		jio.Outputln("dry run, the transaction will be signed and simulated but not sent...")
+	} else {
+		response := jio.MustInputWithPrompt("WARNING: you are about to send the above transaction to the ethereum network, please double check the summary above for accuracy, this cannot be undone if successful. PROCEED? [y/N]: ")
+		response = strings.ToLower(response)
+		if response != "y" && response != "yes" {
+			jio.Output("aborting...")
+			return
+		}
+		jio.Outputln("proceeding...")
+	}
+
+	chainId := env.Chain.ID
+
+	jio.Outputln("building transaction...")
+	tx := txFees.tx(chainId, s.nonce, to, value, data, gasLimit)
+	jio.Outputln("transaction built successfully")
+
+	if cfg.signerKind == signerRemote {
+		jio.Outputln("waiting for the remote signer to approve the transaction...")
+	}
+	signedTx, err := s.signer.SignTx(ctx, tx, chainId)
+	if err != nil {
+		panic(errors.Wrap(err, "signing transaction"))
+	}
+	cli.DestroySigner(s.signer)
+	jio.Outputln("transaction signed successfully")
+
+	signedTxJsonBytes, err := signedTx.MarshalJSON()
+	if err != nil {
+		panic(errors.Wrap(err, "marshalling signed transaction into json"))
+	}
+	signedTxJson := string(signedTxJsonBytes)
+	jio.SilentOutputln("")
+	jio.Outputln("signed transaction json:")
+	jio.SilentOutputln(signedTxJson)
+	jio.SilentOutputln("")
+
+	if cfg.dryRun {
+		signedTxBytes, err := signedTx.MarshalBinary()
+		if err != nil {
+			panic(errors.Wrap(err, "encoding signed transaction"))
+		}
+		jio.Outputln("signed transaction raw hex:")
+		jio.SilentOutputln(hexutil.Encode(signedTxBytes))
+		jio.SilentOutputln("")
+
+		if err := simulate(ctx, s.client, s.sender.Common(), signedTx); err != nil {
+			jio.Outputf("dry run: the transaction would FAIL: %v\n", err)
+			cli.Exit(cli.ExitRejected)
+		}
+		jio.Outputf("dry run: the transaction would succeed, it was NOT sent: transaction hash if sent: %s\n", signedTx.Hash().Hex())
+		return
+	}
+
+	err = s.client.SendTransaction(ctx, signedTx)
+	if err != nil {
+		panic(errors.Wrap(err, "sending transaction"))
+	}
+	jio.Outputf("success: transaction hash: [TRANSACTION] %s\n", signedTx.Hash().Hex())
+}
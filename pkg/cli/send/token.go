@@ -0,0 +1,113 @@
+package send
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jeth/pkg/convert"
+	"github.com/Insulince/jeth/pkg/erc20"
+	"github.com/Insulince/jeth/pkg/eth"
+	"github.com/Insulince/jeth/pkg/price"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+// token is the ERC-20 token given via "-token", as read from its contract.
+type token struct {
+	address  wallet.Address
+	symbol   string
+	decimals uint8
+	// balance is the sender's balance of the token, in its smallest unit.
+	balance *big.Int
+	// usdPerToken is the price of one whole token in dollars, it is 0 if the token could not be priced.
+	usdPerToken float64
+}
+
+// getToken reads the symbol and decimals of the ERC-20 token at address on the chain chainID, and holder's balance of it, then prices it.
+// Only well-known tokens are priced, by address and never by the symbol the contract claims, see price.UsdPerToken.
+// A token which cannot be priced is not an error, its dollar values are only left out.
+func getToken(ctx context.Context, client *ethclient.Client, chainID *big.Int, address, holder wallet.Address) (token, error) {
+	t := token{address: address}
+	var err error
+	if t.decimals, err = erc20.Decimals(ctx, client, address.Common()); err != nil {
+		return token{}, errors.Wrap(err, "reading token's decimals")
+	}
+	if t.symbol, err = erc20.Symbol(ctx, client, address.Common()); err != nil {
+		return token{}, errors.Wrap(err, "reading token's symbol")
+	}
+	if t.balance, err = erc20.BalanceOf(ctx, client, address.Common(), holder.Common()); err != nil {
+		return token{}, errors.Wrap(err, "reading sender's token balance")
+	}
+
+	if t.usdPerToken, err = price.UsdPerToken(chainID, address.Common()); err != nil {
+		jio.Outputf("WARNING: could not fetch the price of %s, dollar values of it will not be shown: %v\n", t.symbol, err)
+		t.usdPerToken = 0
+	}
+
+	return t, nil
+}
+
+// amount returns the amount of t cfg sends, in its smallest unit, checking that the sender's balance of t covers it.
+// If the balance does not cover it the returned error wraps eth.ErrInsufficientFunds.
+func (t token) amount(cfg Config) (*big.Int, error) {
+	if cfg.sendMax {
+		if t.balance.Sign() == 0 {
+			return nil, errors.Wrapf(eth.ErrInsufficientFunds, "sender has no %s to send", t.symbol)
+		}
+		return new(big.Int).Set(t.balance), nil
+	}
+
+	amount, err := erc20.ParseUnits(cfg.amountText, t.decimals)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing token amount")
+	}
+	if amount.Sign() == 0 {
+		return nil, fmt.Errorf("amount \"%s\" is less than the smallest unit of %s", cfg.amountText, t.symbol)
+	}
+	if t.balance.Cmp(amount) < 0 {
+		return nil, errors.Wrapf(eth.ErrInsufficientFunds, "token balance of %s cannot cover the amount of %s", t.format(t.balance), t.format(amount))
+	}
+	return amount, nil
+}
+
+// format formats amount of t, in its smallest unit, in whole tokens with its approximate value in dollars.
+func (t token) format(amount *big.Int) string {
+	if t.usdPerToken == 0 {
+		return fmt.Sprintf("%s %s ($?)", erc20.FormatUnits(amount, t.decimals), t.symbol)
+	}
+
+	unit := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.decimals)), nil))
+	usd := new(big.Float).Mul(new(big.Float).Quo(new(big.Float).SetInt(amount), unit), big.NewFloat(t.usdPerToken))
+	return fmt.Sprintf("%s %s ($%.2f)", erc20.FormatUnits(amount, t.decimals), t.symbol, convert.F(usd))
+}
+
+// coversFee checks that the sender's ether balance covers the worst-case fee of a token transfer, which is paid in ether however much of the token is sent.
+// If it does not the returned error wraps eth.ErrInsufficientFunds.
+func coversFee(balance, fee *big.Int) error {
+	if balance.Cmp(fee) < 0 {
+		return errors.Wrapf(eth.ErrInsufficientFunds, "ether balance of %s wei cannot cover the worst-case fee of %s wei, a token transfer's fee is paid in ether", balance, fee)
+	}
+	return nil
+}
+
+// summarizeToken describes the transfer of amount of t, in its smallest unit, for the summary shown before sending. balance is the sender's ether balance.
+func summarizeToken(t token, amount, balance *big.Int, txFees fees, txGasLimit gasLimit, tokenAddress, senderWalletAddress, receiverWalletAddress string, usdPerEth float64) string {
+	worstCase := txFees.worstCase(txGasLimit.limit)
+
+	return fmt.Sprintf("AMOUNT SENDING (exact, the fee is paid in ether): %s\nTOKEN:\t%s (%s, %v decimals)\nTOKEN BALANCE: %s, %s left after\n%s%sWORST-CASE TOTAL COST: %s and %s\nETHER BALANCE: %s, at least %s left after\nFROM:\t%s\nTO:\t%s\n",
+		t.format(amount),
+		tokenAddress, t.symbol, t.decimals,
+		t.format(t.balance), t.format(new(big.Int).Sub(t.balance, amount)),
+		txGasLimit.summary(),
+		txFees.summary(txGasLimit.limit, usdPerEth),
+		t.format(amount), ether(worstCase, usdPerEth),
+		ether(balance, usdPerEth), ether(new(big.Int).Sub(balance, worstCase), usdPerEth),
+		senderWalletAddress,
+		receiverWalletAddress,
+	)
+}
@@ -0,0 +1,79 @@
+package signmessage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Insulince/jeth/pkg/cli"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// Command is "jeth sign-message".
+var Command = &cli.Command{
+	Name:    "sign-message",
+	Summary: "sign a message with EIP-191 personal_sign",
+	Args:    "[flags]",
+	Description: `
+Signs a message as personal_sign does, proving the signer holds the wallet's private key without sending a transaction.
+The signature is verified before it is printed, along with the signed message in the JSON format MyEtherWallet and MyCrypto share, which verify-message accepts.`,
+	Examples: []string{
+		"sign-message -keystore ./keystore.json -message \"I own this address\"",
+		"sign-message -keystore ./keystore.json -message-file statement.txt",
+		"sign-message -keystore ./keystore.json -hex -message 0xdeadbeef",
+	},
+	Run: run,
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	var privateKeyHex string
+	var keystorePath string
+	var message string
+	var messageFile string
+	var isHex bool
+
+	env.Flags.StringVar(&privateKeyHex, "private-key", "", "the signer's private key, 64 hexadecimal characters, no 0x prefix [required via flag, -keystore, or stdin at runtime]")
+	env.Flags.StringVar(&keystorePath, "keystore", "", "path to a keystore file holding the signer's private key, its passphrase is prompted for on stdin")
+	env.Flags.StringVar(&message, "message", "", "the message to sign [required via flag, -message-file, or stdin at runtime]")
+	env.Flags.StringVar(&messageFile, "message-file", "", "path to a file whose exact contents are the message to sign")
+	env.Flags.BoolVar(&isHex, "hex", false, "treat the message as \"0x\" prefixed hexadecimal bytes rather than text")
+	env.Parse(args)
+
+	w, err := cli.LoadWallet(nil, privateKeyHex, keystorePath)
+	if err != nil {
+		panic(errors.Wrap(err, "loading signer's wallet"))
+	}
+
+	messageBytes, err := cli.LoadMessage(message, messageFile, isHex)
+	if err != nil {
+		panic(errors.Wrap(err, "loading message"))
+	}
+
+	signature, err := w.SignMessage(messageBytes)
+	if err != nil {
+		panic(errors.Wrap(err, "signing message"))
+	}
+
+	// Verify the signature before handing it to anyone, it costs nothing and catches a broken wallet.
+	if err := w.VerifyMessage(messageBytes, signature); err != nil {
+		panic(errors.Wrap(err, "verifying signature"))
+	}
+
+	sm := cli.SignedMessage{
+		Address:   w.Addr(),
+		Message:   string(messageBytes),
+		Signature: hexutil.Encode(signature),
+		Version:   "2",
+	}
+	if isHex {
+		sm.Message = hexutil.Encode(messageBytes)
+	}
+	smJSON, err := json.MarshalIndent(sm, "", "  ")
+	if err != nil {
+		panic(errors.Wrap(err, "marshalling signed message"))
+	}
+
+	fmt.Printf("SIGNER ADDRESS:\n%s\n\nMESSAGE (%v bytes):\n%s\n\nSIGNATURE (EIP-191 personal_sign, r || s || v):\n%s\n\nSIGNED MESSAGE:\n%s\n\nSignature has been verified against the signer's address.\n", sm.Address, len(messageBytes), sm.Message, sm.Signature, smJSON)
+}
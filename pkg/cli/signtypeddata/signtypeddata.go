@@ -0,0 +1,96 @@
+package signtypeddata
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/eip712"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// Command is "jeth sign-typed-data".
+var Command = &cli.Command{
+	Name:    "sign-typed-data",
+	Summary: "sign EIP-712 typed data",
+	Args:    "[flags]",
+	Description: `
+Signs an EIP-712 typed data document as eth_signTypedData_v4 does, such as a permit or an order.
+The domain separator, message hash, and digest are shown before the key is loaded, and the signature is verified before it is printed.`,
+	Examples: []string{
+		"sign-typed-data -keystore ./keystore.json -typed-data-file permit.json",
+	},
+	Run: run,
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	var privateKeyHex string
+	var keystorePath string
+	var typedDataFile string
+
+	env.Flags.StringVar(&privateKeyHex, "private-key", "", "the signer's private key [required via flag, -keystore, or stdin at runtime]")
+	env.Flags.StringVar(&keystorePath, "keystore", "", "path to a keystore file holding the signer's private key, its passphrase is prompted for on stdin")
+	env.Flags.StringVar(&typedDataFile, "typed-data-file", "", "path to the EIP-712 typed data JSON document (types, primaryType, domain, message) to sign, or \"-\" to read it from stdin, which then requires -private-key [required]")
+	env.Parse(args)
+
+	if typedDataFile == "" {
+		panic(errors.New("typed data file cannot be blank, please provide a path via -typed-data-file"))
+	}
+	// Stdin then holds the typed data, so neither the private key nor a keystore's passphrase can be prompted for on it.
+	if typedDataFile == "-" && (privateKeyHex == "" || keystorePath != "") {
+		cli.Usagef("typed data is read from stdin with \"-typed-data-file -\", so the private key must be given via -private-key, -keystore's passphrase is also read from stdin")
+	}
+
+	var typedDataBytes []byte
+	var err error
+	if typedDataFile == "-" {
+		typedDataBytes, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		typedDataBytes, err = ioutil.ReadFile(typedDataFile)
+	}
+	if err != nil {
+		panic(errors.Wrap(err, "reading typed data"))
+	}
+
+	td, err := eip712.Parse(typedDataBytes)
+	if err != nil {
+		panic(errors.Wrap(err, "parsing typed data"))
+	}
+
+	domainSeparator, err := td.DomainSeparator()
+	if err != nil {
+		panic(errors.Wrap(err, "hashing domain"))
+	}
+	messageHash, err := td.MessageHash()
+	if err != nil {
+		panic(errors.Wrap(err, "hashing message"))
+	}
+	digest, err := td.Digest()
+	if err != nil {
+		panic(errors.Wrap(err, "computing digest"))
+	}
+
+	// Show exactly what is about to be signed before the key is even loaded.
+	fmt.Printf("PRIMARY TYPE:\n%s\n\nDOMAIN SEPARATOR:\n%s\n\nMESSAGE HASH:\n%s\n\nDIGEST (signed):\n%s\n\n", td.PrimaryType, hexutil.Encode(domainSeparator), hexutil.Encode(messageHash), hexutil.Encode(digest))
+
+	w, err := cli.LoadWallet(nil, privateKeyHex, keystorePath)
+	if err != nil {
+		panic(errors.Wrap(err, "loading signer's wallet"))
+	}
+
+	signature, err := eip712.Sign(w, td)
+	if err != nil {
+		panic(errors.Wrap(err, "signing typed data"))
+	}
+
+	// Verify the signature before handing it to anyone, it costs nothing and catches a broken wallet.
+	if err := eip712.Verify(w.Address(), td, signature); err != nil {
+		panic(errors.Wrap(err, "verifying signature"))
+	}
+
+	fmt.Printf("SIGNER ADDRESS:\n%s\n\nSIGNATURE (EIP-712 eth_signTypedData_v4, r || s || v):\n%s\n\nSignature has been verified against the signer's address.\n", w.Address(), hexutil.Encode(signature))
+}
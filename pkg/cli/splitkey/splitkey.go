@@ -0,0 +1,88 @@
+package splitkey
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/shamir"
+
+	"github.com/pkg/errors"
+)
+
+// Command is "jeth split-key".
+var Command = &cli.Command{
+	Name:    "split-key",
+	Summary: "split a private key into shares, any threshold of which recover it",
+	Args:    "[flags]",
+	Description: `
+Splits a wallet's private key with Shamir's secret sharing into -shares shares, any -threshold of which recover it with recover-key, while fewer reveal nothing.
+The shares are recovered back into the wallet before they are shown.`,
+	Examples: []string{
+		"split-key -keystore ./keystore.json",
+		"split-key -keystore ./keystore.json -shares 3 -threshold 2 -out-dir ./shares",
+	},
+	Run: run,
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	var privateKeyHex string
+	var keystorePath string
+	var total int
+	var threshold int
+	var outDir string
+
+	env.Flags.StringVar(&privateKeyHex, "private-key", "", "the private key to split [required via flag, -keystore, or stdin at runtime]")
+	env.Flags.StringVar(&keystorePath, "keystore", "", "path to a keystore file holding the private key to split, its passphrase is prompted for on stdin")
+	env.Flags.IntVar(&total, "shares", 5, fmt.Sprintf("the number of shares to split the private key into, at most %v", shamir.MaxShares))
+	env.Flags.IntVar(&threshold, "threshold", 3, "the number of shares needed to recover the private key, at least 2")
+	env.Flags.StringVar(&outDir, "out-dir", "", "if set, write each share into its own file in this directory instead of only displaying them")
+	env.Parse(args)
+
+	w, err := cli.LoadWallet(nil, privateKeyHex, keystorePath)
+	if err != nil {
+		panic(errors.Wrap(err, "loading wallet"))
+	}
+
+	shares, err := shamir.Split(w, total, threshold)
+	if err != nil {
+		panic(errors.Wrap(err, "splitting private key"))
+	}
+
+	// Recover the wallet from the first threshold shares, and from the last, before anyone relies on them.
+	for _, subset := range [][]shamir.Share{shares[:threshold], shares[total-threshold:]} {
+		w2, err := shamir.Combine(subset)
+		if err != nil {
+			panic(errors.Wrap(err, "recovering wallet from shares"))
+		}
+		if err := w.Equals(w2); err != nil {
+			panic(errors.Wrap(err, "shares do not recover the original wallet"))
+		}
+	}
+
+	fmt.Printf("WALLET ADDRESS:\n%s\n\nSplit into %v shares, any %v of which recover the private key. Give each share to a different person and never store %v of them together.\n", w.Address(), total, threshold, threshold)
+
+	if outDir != "" {
+		if err := os.MkdirAll(outDir, 0700); err != nil {
+			panic(errors.Wrap(err, "creating output directory"))
+		}
+	}
+	for _, s := range shares {
+		if outDir == "" {
+			fmt.Printf("\nSHARE %v OF %v:\n%s\n", s.Index, s.Total, s)
+			continue
+		}
+
+		// A share on disk is as sensitive as a share on paper, only its owner may read it.
+		path := filepath.Join(outDir, fmt.Sprintf("%s--share-%v-of-%v.txt", w.Address(), s.Index, s.Total))
+		if err := ioutil.WriteFile(path, []byte(s.String()+"\n"), 0600); err != nil {
+			panic(errors.Wrapf(err, "writing share %v", s.Index))
+		}
+		fmt.Printf("\nSHARE %v OF %v:\nwritten to %s\n", s.Index, s.Total, path)
+	}
+
+	fmt.Printf("\nShares have been recovered into the original wallet and are correct.\n")
+}
@@ -0,0 +1,206 @@
+package storecmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/store"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/pkg/errors"
+)
+
+// Command is "jeth store".
+var Command = &cli.Command{
+	Name:    "store",
+	Summary: "manage the local store of named wallets and contacts",
+	Args:    "<command> [flags]",
+	Description: `
+Manages the local store of named wallets and contacts, kept in $JETH_HOME or ~/.jeth.
+Stored entries can be referred to as "@name" wherever send and check-balance expect an address.
+
+commands:
+  add     add a watch-only wallet, keystore-backed wallet, or contact
+  list    list every entry
+  rename  rename an entry, "store rename <from> <to>"
+  remove  remove an entry, "store remove <name>"
+
+Each command takes "-h" for its flags, such as "store add -h".`,
+	Examples: []string{
+		"store add -name savings -address 0x19325d2D5c17AF1096D28A12850D27bD182612F6",
+		"store add -name main -keystore ./keystore.json",
+		"store add -name alice -address 0x19325d2D5c17AF1096D28A12850D27bD182612F6 -contact -label \"Alice (payroll)\"",
+		"store list",
+		"store rename main hot",
+		"store remove alice",
+	},
+	Run: run,
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	env.Parse(args)
+	if env.Flags.NArg() < 1 {
+		cli.Usagef("missing command, must be one of \"add\", \"list\", \"rename\", or \"remove\"")
+	}
+
+	s, err := store.OpenDefault()
+	if err != nil {
+		panic(errors.Wrap(err, "opening store"))
+	}
+
+	command, args := env.Flags.Arg(0), env.Flags.Args()[1:]
+	switch command {
+	case "add":
+		add(env, s, args)
+	case "list":
+		list(env, s, args)
+	case "rename":
+		rename(env, s, args)
+	case "remove":
+		remove(env, s, args)
+	default:
+		cli.Usagef("unknown command \"%s\", must be one of \"add\", \"list\", \"rename\", or \"remove\"", command)
+	}
+}
+
+func add(env *cli.Env, s *store.Store, args []string) {
+	var name string
+	var address wallet.Address
+	var keystorePath string
+	var isContact bool
+	var label string
+	var notes string
+
+	fs := subcommand(env, "add")
+	fs.StringVar(&name, "name", "", "the name to refer to the entry by, as \"@name\" [required]")
+	fs.Var(&address, "address", "the address of a watch-only wallet or contact, mixed case addresses must have a valid EIP-55 checksum")
+	fs.StringVar(&keystorePath, "keystore", "", "the path to a keystore file to copy into the store as a keystore-backed wallet, instead of -address")
+	fs.BoolVar(&isContact, "contact", false, "add -address as someone else's address rather than as one of your own watch-only wallets")
+	fs.StringVar(&label, "label", "", "a short description shown next to the address, such as \"Alice (payroll)\"")
+	fs.StringVar(&notes, "notes", "", "free text notes")
+	parse(fs, args)
+
+	e := store.Entry{
+		Name:    name,
+		Kind:    store.KindWallet,
+		Address: address,
+		Label:   label,
+		Notes:   notes,
+	}
+	if isContact {
+		e.Kind = store.KindContact
+	}
+
+	switch {
+	case !address.IsZero() && keystorePath != "":
+		panic(errors.New("must provide only one of -address or -keystore"))
+	case keystorePath != "":
+		if err := s.AddKeystore(e, keystorePath); err != nil {
+			panic(errors.Wrap(err, "adding keystore-backed wallet"))
+		}
+	case !address.IsZero():
+		if err := s.Add(e); err != nil {
+			panic(errors.Wrap(err, "adding entry"))
+		}
+	default:
+		panic(errors.New("must provide an address via -address or a keystore file via -keystore"))
+	}
+
+	save(s)
+
+	e, err := s.Get(name)
+	if err != nil {
+		panic(errors.Wrap(err, "reading back entry"))
+	}
+	fmt.Printf("added %s %s%s: %s\n", describe(e), store.RefPrefix, e.Name, e.Address)
+}
+
+func list(env *cli.Env, s *store.Store, args []string) {
+	fs := subcommand(env, "list")
+	parse(fs, args)
+
+	entries := s.List()
+	if len(entries) == 0 {
+		fmt.Printf("the store in %s is empty, add to it with \"store add\"\n", s.Dir())
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAME\tKIND\tADDRESS\tLABEL\tNOTES")
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(tw, "%s%s\t%s\t%s\t%s\t%s\n", store.RefPrefix, e.Name, describe(e), e.Address, e.Label, e.Notes)
+	}
+	_ = tw.Flush()
+}
+
+func rename(env *cli.Env, s *store.Store, args []string) {
+	fs := subcommand(env, "rename")
+	parse(fs, args)
+	if fs.NArg() != 2 {
+		cli.Usagef("usage: %s rename <from> <to>", env.Program())
+	}
+
+	if err := s.Rename(fs.Arg(0), fs.Arg(1)); err != nil {
+		panic(errors.Wrap(err, "renaming entry"))
+	}
+	save(s)
+
+	fmt.Printf("renamed %s to %s\n", fs.Arg(0), fs.Arg(1))
+}
+
+func remove(env *cli.Env, s *store.Store, args []string) {
+	fs := subcommand(env, "remove")
+	parse(fs, args)
+	if fs.NArg() != 1 {
+		cli.Usagef("usage: %s remove <name>", env.Program())
+	}
+
+	e, err := s.Remove(fs.Arg(0))
+	if err != nil {
+		panic(errors.Wrap(err, "removing entry"))
+	}
+	save(s)
+
+	fmt.Printf("removed %s %s%s: %s\n", describe(e), store.RefPrefix, e.Name, e.Address)
+	if e.IsKeystoreBacked() {
+		fmt.Printf("its keystore file was kept, it may be the only copy of the key, delete it yourself once you are sure it is backed up:\n%s\n", s.KeystorePath(e))
+	}
+}
+
+// subcommand returns the flag set of the store command name.
+func subcommand(env *cli.Env, name string) *flag.FlagSet {
+	return flag.NewFlagSet(env.Program()+" "+name, flag.ContinueOnError)
+}
+
+// parse parses args with fs, exiting as cli.Env.Parse does if they are invalid or "-help" is given.
+func parse(fs *flag.FlagSet, args []string) {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			cli.Exit(cli.ExitOK)
+		}
+		cli.Exit(cli.ExitUsage)
+	}
+}
+
+// save saves s, panicking if it cannot.
+func save(s *store.Store) {
+	if err := s.Save(); err != nil {
+		panic(errors.Wrap(err, "saving store"))
+	}
+}
+
+// describe returns a short description of e's kind.
+func describe(e store.Entry) string {
+	switch {
+	case e.Kind == store.KindContact:
+		return "contact"
+	case e.IsKeystoreBacked():
+		return "keystore wallet"
+	default:
+		return "watch-only wallet"
+	}
+}
@@ -0,0 +1,284 @@
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/convert"
+	"github.com/Insulince/jeth/pkg/eth"
+	"github.com/Insulince/jeth/pkg/signer"
+	"github.com/Insulince/jeth/pkg/store"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+const (
+	// transferGasLimit is the exact gas a plain ether transfer to an account without code uses.
+	transferGasLimit = uint64(21000)
+	// suggestedGasPrice means -gas-price was not given, so the network's suggested gas price is used.
+	suggestedGasPrice = 0
+)
+
+// Command is "jeth sweep".
+var Command = &cli.Command{
+	Name:    "sweep",
+	Summary: "move a wallet's entire balance to another wallet, or to a newly generated one",
+	Args:    "[flags]",
+	Description: `
+Moves the entire balance of a wallet, leaving nothing behind, to -receiver-address or to a new wallet generated into -new-wallet-dir.
+The transaction is a legacy transfer with the exact gas a transfer uses, so the fee is known in advance and the value is the balance minus it.
+Refuses to sweep while the wallet has pending transactions, or to a contract.`,
+	Examples: []string{
+		"sweep -keystore @old -receiver-address @savings",
+		"sweep -keystore ./compromised.json -new-wallet-dir ./keystores -new-wallet-name fresh",
+	},
+	Run: run,
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	var privateKeyHex string
+	var keystorePath string
+	var receiverRef string
+	var newWalletDir string
+	var newWalletName string
+	var kdf string
+	var gasPrice int64
+
+	env.Flags.StringVar(&privateKeyHex, "private-key", "", "the hexadecimal private key of the wallet to sweep [required via flag or stdin at runtime, unless -keystore is given]")
+	env.Flags.StringVar(&keystorePath, "keystore", "", "the path to the passphrase encrypted keystore file of the wallet to sweep, or \"@name\" of a keystore-backed wallet in the store")
+	env.Flags.StringVar(&receiverRef, "receiver-address", "", "the address to move the entire balance to, or \"@name\" of a wallet or contact in the store, instead of a new wallet")
+	env.Flags.StringVar(&newWalletDir, "new-wallet-dir", "", "generate a new wallet to move the entire balance to, written into this directory as a passphrase encrypted keystore file before anything is sent")
+	env.Flags.StringVar(&newWalletName, "new-wallet-name", "", "for -new-wallet-dir, also add the new wallet to the store under this name")
+	env.Flags.StringVar(&kdf, "kdf", string(wallet.KDFScrypt), "for -new-wallet-dir, the key derivation function to use for the keystore file, \"scrypt\" or \"pbkdf2\"")
+	env.Flags.Int64Var(&gasPrice, "gas-price", suggestedGasPrice, "the gas price in wei, or 0 to use the network's suggested gas price")
+	env.Parse(args)
+
+	jio.Outputf("sweep initiated at %v\n", time.Now().Format(time.RFC3339Nano))
+	defer func() { jio.Outputf("sweep completed at %v\n", time.Now().Format(time.RFC3339Nano)) }()
+
+	addressBook, err := store.OpenDefault()
+	if err != nil {
+		panic(errors.Wrap(err, "opening store"))
+	}
+
+	if (receiverRef == "") == (newWalletDir == "") {
+		panic(errors.New("must provide exactly one of -receiver-address or -new-wallet-dir"))
+	}
+	if newWalletName != "" && newWalletDir == "" {
+		panic(errors.New("-new-wallet-name is only used with -new-wallet-dir"))
+	}
+	// Checked now, as the new wallet's keystore file is written before it is added to the store.
+	if newWalletName != "" {
+		if err := addressBook.CheckName(newWalletName); err != nil {
+			panic(errors.Wrap(err, "checking -new-wallet-name"))
+		}
+	}
+	opts, err := cli.KeystoreOptions(kdf)
+	if err != nil {
+		panic(err)
+	}
+	if gasPrice < 0 {
+		panic(fmt.Errorf("gas price must not be negative, got %v", gasPrice))
+	}
+
+	txSigner := getSigner(addressBook, privateKeyHex, keystorePath)
+	// Wipe any key material and close any connection the signer holds however sweep ends, panics included. It is wiped sooner still once the transaction is signed.
+	defer cli.DestroySigner(txSigner)
+	defer cli.CloseSigner(txSigner)
+	privateKeyHex = ""
+
+	sender, err := wallet.ParseAddress(txSigner.Address())
+	if err != nil {
+		panic(errors.Wrap(err, "parsing signer's address"))
+	}
+	jio.Outputf("sweeping wallet: [WALLET] %s\n", addressBook.Display(sender))
+
+	client, err := env.Dial(ctx)
+	if err != nil {
+		panic(err)
+	}
+	defer client.Close()
+	jio.Outputf("connected to gateway: %s (%s)\n", env.Gateway, env.Chain.Describe())
+
+	// A transaction still in the mempool would spend from the balance too, so the sweep would fail or leave some behind.
+	nonce, err := client.NonceAt(ctx, sender.Common(), eth.LatestBlock)
+	if err != nil {
+		panic(errors.Wrap(err, "fetching nonce"))
+	}
+	pendingNonce, err := client.PendingNonceAt(ctx, sender.Common())
+	if err != nil {
+		panic(errors.Wrap(err, "fetching pending nonce"))
+	}
+	if pendingNonce != nonce {
+		panic(fmt.Errorf("%s has %v pending transactions, wait for them to be mined before sweeping it", sender, pendingNonce-nonce))
+	}
+
+	balance, err := client.BalanceAt(ctx, sender.Common(), eth.LatestBlock)
+	if err != nil {
+		panic(errors.Wrap(err, "fetching balance"))
+	}
+	jio.Outputf("balance: %s wei (%s ether)\n", balance, convert.WeiIToEth(balance).String())
+
+	bGasPrice := big.NewInt(gasPrice)
+	if gasPrice == suggestedGasPrice {
+		if bGasPrice, err = client.SuggestGasPrice(ctx); err != nil {
+			panic(errors.Wrap(err, "getting suggested gas price"))
+		}
+	}
+	jio.Outputf("using gas price: %s wei\n", bGasPrice)
+
+	// Check the balance covers the fee before generating a new wallet, so nothing is left lying around when there is nothing to sweep.
+	value, err := eth.SweepValue(balance, bGasPrice, transferGasLimit)
+	if err != nil {
+		panic(errors.Wrap(err, "computing value to sweep"))
+	}
+	fee := new(big.Int).Sub(balance, value)
+
+	var receiver wallet.Address
+	if newWalletDir != "" {
+		receiver = newWallet(addressBook, newWalletDir, newWalletName, opts)
+	} else {
+		receiver, _, err = addressBook.Resolve(receiverRef)
+		if err != nil {
+			panic(errors.Wrap(err, "resolving -receiver-address"))
+		}
+	}
+	if receiver.IsZero() {
+		panic(errors.New("must not sweep to the zero address, funds sent to it are lost"))
+	}
+	if receiver == sender {
+		panic(errors.New("must not sweep a wallet to itself"))
+	}
+
+	// A contract may use more gas than a plain transfer, or revert, either way the fee could not be known exactly.
+	code, err := client.CodeAt(ctx, receiver.Common(), eth.LatestBlock)
+	if err != nil {
+		panic(errors.Wrap(err, "fetching receiver's code"))
+	}
+	if len(code) > 0 {
+		panic(fmt.Errorf("%s is a contract, sweep only sends to accounts without code so the fee is exact", receiver))
+	}
+
+	jio.SilentOutputln("")
+	jio.Outputln("----- SUMMARY -----")
+	jio.SilentOutputln(fmt.Sprintf("BALANCE: %s ether (%s wei)\nFEE: %s wei price * %v limit = %s wei (%s ether)\nSWEEPING: %s ether (%s wei), leaving 0 behind\nFROM:\t%s\nTO:\t%s\n",
+		convert.WeiIToEth(balance).String(), balance,
+		bGasPrice, transferGasLimit, fee, convert.WeiIToEth(fee).String(),
+		convert.WeiIToEth(value).String(), value,
+		addressBook.Display(sender),
+		addressBook.Display(receiver),
+	))
+
+	response := jio.MustInputWithPrompt("WARNING: you are about to move the ENTIRE balance above to the receiver, this cannot be undone if successful. PROCEED? [y/N]: ")
+	response = strings.ToLower(response)
+	if response != "y" && response != "yes" {
+		jio.Output("aborting...")
+		return
+	}
+
+	// The fee of a legacy transaction is always its gas price times the gas used, which is what makes the sweep exact.
+	to := receiver.Common()
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       &to,
+		Value:    value,
+		Gas:      transferGasLimit,
+		GasPrice: bGasPrice,
+	})
+	signedTx, err := txSigner.SignTx(ctx, tx, env.Chain.ID)
+	if err != nil {
+		panic(errors.Wrap(err, "signing transaction"))
+	}
+	cli.DestroySigner(txSigner)
+	jio.Outputln("transaction signed successfully")
+
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		panic(errors.Wrap(err, "sending transaction"))
+	}
+	jio.Outputf("success: transaction hash: [TRANSACTION] %s\n", signedTx.Hash().Hex())
+	jio.Outputf("once it is mined %s will hold nothing, stop using it if its key may be compromised\n", sender)
+}
+
+// getSigner creates a signer from privateKeyHex, or from the keystore file at keystorePath with a passphrase prompted for on stdin.
+func getSigner(addressBook *store.Store, privateKeyHex, keystorePath string) signer.Signer {
+	if privateKeyHex != "" && keystorePath != "" {
+		panic(errors.New("must provide only one of -private-key or -keystore"))
+	}
+
+	if keystorePath == "" {
+		if privateKeyHex == "" {
+			privateKeyHex = jio.MustPrivateInputWithPrompt("private key of the wallet to sweep not given via \"-private-key\" flag, enter manually instead: ")
+			jio.SilentOutputln("")
+		}
+		w, err := wallet.FromPrivateKeyHex(privateKeyHex)
+		if err != nil {
+			panic(errors.Wrap(err, "parsing private key"))
+		}
+		return signer.NewWallet(w)
+	}
+
+	keystorePath, err := cli.ResolveKeystore(addressBook, keystorePath)
+	if err != nil {
+		panic(err)
+	}
+
+	passphrase := jio.MustPrivateInputWithPrompt(fmt.Sprintf("enter the passphrase for keystore file \"%s\": ", keystorePath))
+	jio.SilentOutputln("")
+	s, err := signer.NewKeystore(keystorePath, passphrase)
+	if err != nil {
+		panic(errors.Wrap(err, "opening keystore file"))
+	}
+	return s
+}
+
+// newWallet generates a new wallet and writes it into dir as a keystore file encrypted with a passphrase prompted for on stdin,
+// reading it back to be certain it can be recovered before anything is sent to it. If name is set it is also added to addressBook.
+func newWallet(addressBook *store.Store, dir, name string, opts wallet.KeystoreOptions) wallet.Address {
+	w, err := wallet.New()
+	if err != nil {
+		panic(errors.Wrap(err, "generating new wallet"))
+	}
+	defer w.Destroy()
+	if err := w.Validate(); err != nil {
+		panic(errors.Wrap(err, "generated wallet is invalid"))
+	}
+
+	passphrase, err := cli.NewPassphrase("the new wallet's keystore file")
+	if err != nil {
+		panic(err)
+	}
+
+	path, err := w.WriteKeystoreFile(dir, passphrase, opts)
+	if err != nil {
+		panic(errors.Wrap(err, "writing new wallet's keystore file"))
+	}
+	w2, err := wallet.FromKeystoreFile(path, passphrase)
+	if err != nil {
+		panic(errors.Wrap(err, "reading back new wallet's keystore file"))
+	}
+	defer w2.Destroy()
+	if err := w.Equals(w2); err != nil {
+		panic(errors.Wrap(err, "keystore file does not contain the new wallet"))
+	}
+	jio.Outputf("new wallet %s written to keystore file %s, it has been read back and decrypts to the new wallet, back it up before proceeding\n", w.Address(), path)
+
+	if name != "" {
+		if err := addressBook.AddKeystore(store.Entry{Name: name, Kind: store.KindWallet}, path); err != nil {
+			panic(errors.Wrap(err, "adding new wallet to store"))
+		}
+		if err := addressBook.Save(); err != nil {
+			panic(errors.Wrap(err, "saving store"))
+		}
+		jio.Outputf("new wallet added to the store as %s%s\n", store.RefPrefix, name)
+	}
+
+	return w.Addr()
+}
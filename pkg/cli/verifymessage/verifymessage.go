@@ -0,0 +1,124 @@
+package verifymessage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/Insulince/jeth/pkg/cli"
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	jio "github.com/Insulince/jlib/pkg/io"
+)
+
+// Command is "jeth verify-message".
+var Command = &cli.Command{
+	Name:    "verify-message",
+	Summary: "verify an EIP-191 signed message, or recover who signed it",
+	Args:    "[flags]",
+	Description: `
+Recovers the address which signed a message with personal_sign, as sign-message does, and verifies it is -address.
+Without -address the recovered signer is only displayed, since any valid signature recovers to some address.`,
+	Examples: []string{
+		"verify-message -address 0x19325d2D5c17AF1096D28A12850D27bD182612F6 -message hello -signature 0x...",
+		"verify-message -signed-message-file signed.json",
+		"verify-message -signed-message-file signed.json -output json",
+	},
+	ExitCodes: map[int]string{
+		cli.ExitRejected: "the message was not signed by -address",
+	},
+	JSON: true,
+	Run:  run,
+}
+
+// result is verify-message's "-output json" result.
+type result struct {
+	Signer string `json:"signer"`
+	// Address and Valid are only set when an address to verify against was given.
+	Address *wallet.Address `json:"address,omitempty"`
+	Valid   *bool           `json:"valid,omitempty"`
+}
+
+func run(ctx context.Context, env *cli.Env, args []string) {
+	var address wallet.Address
+	var message string
+	var messageFile string
+	var isHex bool
+	var signatureHex string
+	var signedMessageFile string
+
+	env.Flags.Var(&address, "address", "the address which is claimed to have signed the message, if blank the recovered signer is only displayed, not verified")
+	env.Flags.StringVar(&message, "message", "", "the message which was signed [required via flag, -message-file, -signed-message-file, or stdin at runtime]")
+	env.Flags.StringVar(&messageFile, "message-file", "", "path to a file whose exact contents are the message which was signed")
+	env.Flags.BoolVar(&isHex, "hex", false, "treat the message as \"0x\" prefixed hexadecimal bytes rather than text")
+	env.Flags.StringVar(&signatureHex, "signature", "", "the \"0x\" prefixed 65 byte signature, r || s || v [required via flag, -signed-message-file, or stdin at runtime]")
+	env.Flags.StringVar(&signedMessageFile, "signed-message-file", "", "path to a MyEtherWallet style signed message JSON file, as printed by sign-message, providing the address, message, and signature")
+	env.Parse(args)
+
+	if signedMessageFile != "" {
+		if !address.IsZero() || message != "" || messageFile != "" || signatureHex != "" {
+			panic(errors.New("\"-signed-message-file\" cannot be combined with \"-address\", \"-message\", \"-message-file\", or \"-signature\""))
+		}
+		bs, err := ioutil.ReadFile(signedMessageFile)
+		if err != nil {
+			panic(errors.Wrap(err, "reading signed message file"))
+		}
+		var sm cli.SignedMessage
+		if err := json.Unmarshal(bs, &sm); err != nil {
+			panic(errors.Wrap(err, "unmarshalling signed message file"))
+		}
+		address, message, signatureHex = sm.Address, sm.Message, sm.Signature
+	}
+
+	messageBytes, err := cli.LoadMessage(message, messageFile, isHex)
+	if err != nil {
+		panic(errors.Wrap(err, "loading message"))
+	}
+
+	if signatureHex == "" {
+		signatureHex = jio.MustInputWithPrompt("signature not given via \"-signature\" flag, enter manually instead: ")
+	}
+	signature, err := hexutil.Decode(signatureHex)
+	if err != nil {
+		panic(errors.Wrap(err, "decoding signature"))
+	}
+
+	signer, err := wallet.RecoverMessageSigner(messageBytes, signature)
+	if err != nil {
+		panic(errors.Wrap(err, "recovering signer"))
+	}
+
+	if address.IsZero() {
+		if env.JSON() {
+			cli.PrintJSON(result{Signer: signer})
+			return
+		}
+		fmt.Printf("RECOVERED SIGNER ADDRESS:\n%s\n", signer)
+		fmt.Printf("\nNo \"-address\" given, so the signature was not verified against one. Any valid signature recovers to some address, compare it to the address you expect yourself.\n")
+		return
+	}
+
+	err = wallet.VerifyMessage(address.Hex(), messageBytes, signature)
+	if err != nil && !errors.Is(err, wallet.ErrSignerMismatch) {
+		panic(errors.Wrap(err, "verifying message"))
+	}
+	valid := err == nil
+
+	if env.JSON() {
+		cli.PrintJSON(result{Signer: signer, Address: &address, Valid: &valid})
+	} else {
+		fmt.Printf("RECOVERED SIGNER ADDRESS:\n%s\n", signer)
+		if valid {
+			fmt.Printf("\nVALID: the message was signed by %s.\n", address)
+		} else {
+			fmt.Printf("\nINVALID: the message was NOT signed by %s.\n", address)
+		}
+	}
+	if !valid {
+		cli.Exit(cli.ExitRejected)
+	}
+}
@@ -0,0 +1,509 @@
+package eip712
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DomainType is the name of the struct type every typed data domain is encoded as.
+	DomainType = "EIP712Domain"
+)
+
+var (
+	// domainFields is every field an EIP712Domain may have, in the order the EIP defines them.
+	// When a document does not declare the EIP712Domain type itself, it is inferred from which of these fields its domain has.
+	domainFields = []Field{
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+		{Name: "salt", Type: "bytes32"},
+	}
+
+	// arrayTypeRegex splits an array type, such as "Person[]" or "uint256[2]", into its element type and its optional fixed length.
+	arrayTypeRegex = regexp.MustCompile(`^(.+)\[([0-9]*)\]$`)
+	// identifierRegex matches the names of struct types and their fields.
+	identifierRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+)
+
+// Field is one member of a struct type, its name and its type.
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Types maps the name of every struct type to its fields, in order.
+type Types map[string][]Field
+
+// TypedData is an EIP-712 typed structured data document, the same JSON format eth_signTypedData_v4 and MetaMask accept.
+type TypedData struct {
+	Types       Types                  `json:"types"`
+	PrimaryType string                 `json:"primaryType"`
+	Domain      map[string]interface{} `json:"domain"`
+	Message     map[string]interface{} `json:"message"`
+}
+
+// Parse decodes a typed data JSON document and validates it.
+// Numbers are decoded exactly, so integers beyond the precision of a float64, such as token amounts in wei, survive intact.
+func Parse(data []byte) (*TypedData, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var td TypedData
+	if err := d.Decode(&td); err != nil {
+		return nil, errors.Wrap(err, "decoding typed data")
+	}
+
+	if err := td.Validate(); err != nil {
+		return nil, errors.Wrap(err, "validating typed data")
+	}
+
+	return &td, nil
+}
+
+// Validate checks that td's types are well-formed and that its primary type and domain are declared.
+// It does not check the message against the types, that happens when the message is hashed.
+func (td *TypedData) Validate() error {
+	if td.PrimaryType == "" {
+		return errors.New("primary type must not be blank")
+	}
+	if _, ok := td.Types[td.PrimaryType]; !ok {
+		return fmt.Errorf("primary type \"%s\" is not declared in types", td.PrimaryType)
+	}
+	if td.Domain == nil {
+		return errors.New("domain must not be missing")
+	}
+
+	for name, fields := range td.Types {
+		if !identifierRegex.MatchString(name) {
+			return fmt.Errorf("\"%s\" is not a valid type name", name)
+		}
+		seen := map[string]bool{}
+		for _, f := range fields {
+			if !identifierRegex.MatchString(f.Name) {
+				return fmt.Errorf("type \"%s\" has a field with invalid name \"%s\"", name, f.Name)
+			}
+			if seen[f.Name] {
+				return fmt.Errorf("type \"%s\" has more than one field named \"%s\"", name, f.Name)
+			}
+			seen[f.Name] = true
+			if err := td.checkType(f.Type); err != nil {
+				return errors.Wrapf(err, "checking type of field \"%s\" of type \"%s\"", f.Name, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// EncodeType returns the EIP-712 encoding of primaryType's signature, such as "Mail(Person from,Person to,string contents)Person(string name,address wallet)".
+// primaryType comes first, followed by every struct type it references, directly or indirectly, sorted by name.
+func (td *TypedData) EncodeType(primaryType string) (string, error) {
+	types := td.types()
+	if _, ok := types[primaryType]; !ok {
+		return "", fmt.Errorf("type \"%s\" is not declared", primaryType)
+	}
+
+	dependencies := map[string]bool{}
+	td.collectDependencies(primaryType, dependencies)
+	delete(dependencies, primaryType)
+
+	names := make([]string, 0, len(dependencies))
+	for name := range dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	names = append([]string{primaryType}, names...)
+
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(name)
+		sb.WriteString("(")
+		for i, f := range types[name] {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(f.Type)
+			sb.WriteString(" ")
+			sb.WriteString(f.Name)
+		}
+		sb.WriteString(")")
+	}
+
+	return sb.String(), nil
+}
+
+// TypeHash returns the keccak256 hash of EncodeType(primaryType).
+func (td *TypedData) TypeHash(primaryType string) ([]byte, error) {
+	encodedType, err := td.EncodeType(primaryType)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding type")
+	}
+
+	return ethcrypto.Keccak256([]byte(encodedType)), nil
+}
+
+// HashStruct returns the EIP-712 hashStruct of data as primaryType, keccak256(typeHash || encodeData(data)).
+func (td *TypedData) HashStruct(primaryType string, data map[string]interface{}) ([]byte, error) {
+	typeHash, err := td.TypeHash(primaryType)
+	if err != nil {
+		return nil, errors.Wrap(err, "hashing type")
+	}
+
+	encoded := typeHash
+	for _, f := range td.types()[primaryType] {
+		value, ok := data[f.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s is missing field \"%s\"", primaryType, f.Name)
+		}
+		encodedValue, err := td.encodeValue(f.Type, value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "encoding field \"%s\" of %s", f.Name, primaryType)
+		}
+		encoded = append(encoded, encodedValue...)
+	}
+
+	return ethcrypto.Keccak256(encoded), nil
+}
+
+// DomainSeparator returns the hashStruct of td's domain.
+func (td *TypedData) DomainSeparator() ([]byte, error) {
+	return td.HashStruct(DomainType, td.Domain)
+}
+
+// MessageHash returns the hashStruct of td's message as td's primary type.
+func (td *TypedData) MessageHash() ([]byte, error) {
+	return td.HashStruct(td.PrimaryType, td.Message)
+}
+
+// Digest returns the hash which is actually signed, keccak256("\x19\x01" || domainSeparator || hashStruct(message)).
+func (td *TypedData) Digest() ([]byte, error) {
+	domainSeparator, err := td.DomainSeparator()
+	if err != nil {
+		return nil, errors.Wrap(err, "hashing domain")
+	}
+
+	messageHash, err := td.MessageHash()
+	if err != nil {
+		return nil, errors.Wrap(err, "hashing message")
+	}
+
+	// EIP-191 version 0x01, structured data.
+	encoded := append([]byte{0x19, 0x01}, domainSeparator...)
+	encoded = append(encoded, messageHash...)
+
+	return ethcrypto.Keccak256(encoded), nil
+}
+
+// Sign signs td's Digest with w and returns the signature in its 65 byte r || s || v form, where v is 27 or 28, the same as eth_signTypedData_v4 produces.
+func Sign(w *wallet.Wallet, td *TypedData) ([]byte, error) {
+	digest, err := td.Digest()
+	if err != nil {
+		return nil, errors.Wrap(err, "computing digest")
+	}
+
+	signature, err := w.SignHash(digest)
+	if err != nil {
+		return nil, errors.Wrap(err, "signing digest")
+	}
+
+	signature[wallet.SignatureLength-1] += wallet.SignatureVOffset
+
+	return signature, nil
+}
+
+// RecoverSigner returns the EIP-55 checksummed address which signed td to produce signature.
+// Any valid signature recovers to some address, so the result must still be compared against the address you expected, Verify does this.
+func RecoverSigner(td *TypedData, signature []byte) (string, error) {
+	digest, err := td.Digest()
+	if err != nil {
+		return "", errors.Wrap(err, "computing digest")
+	}
+
+	return wallet.RecoverHashSigner(digest, signature)
+}
+
+// Verify checks that signature is a signature of td made by address.
+// If no error is returned then the signature is valid, otherwise the error wraps wallet.ErrInvalidSignature or wallet.ErrSignerMismatch.
+func Verify(address string, td *TypedData, signature []byte) error {
+	digest, err := td.Digest()
+	if err != nil {
+		return errors.Wrap(err, "computing digest")
+	}
+
+	return wallet.VerifyHash(address, digest, signature)
+}
+
+// types returns td's types, with the EIP712Domain type inferred from td's domain if td does not declare it.
+func (td *TypedData) types() Types {
+	if _, ok := td.Types[DomainType]; ok {
+		return td.Types
+	}
+
+	types := make(Types, len(td.Types)+1)
+	for name, fields := range td.Types {
+		types[name] = fields
+	}
+	var fields []Field
+	for _, f := range domainFields {
+		if _, ok := td.Domain[f.Name]; ok {
+			fields = append(fields, f)
+		}
+	}
+	types[DomainType] = fields
+
+	return types
+}
+
+// collectDependencies adds typeName, and every struct type it references, to found.
+func (td *TypedData) collectDependencies(typeName string, found map[string]bool) {
+	typeName = elementType(typeName)
+	fields, ok := td.types()[typeName]
+	if !ok || found[typeName] {
+		return
+	}
+
+	found[typeName] = true
+	for _, f := range fields {
+		td.collectDependencies(f.Type, found)
+	}
+}
+
+// checkType checks that typeName is either an atomic or dynamic type, a declared struct type, or an array of any of them.
+func (td *TypedData) checkType(typeName string) error {
+	typeName = elementType(typeName)
+	if _, ok := td.types()[typeName]; ok {
+		return nil
+	}
+
+	switch {
+	case typeName == "address" || typeName == "bool" || typeName == "string" || typeName == "bytes":
+		return nil
+	case strings.HasPrefix(typeName, "bytes"):
+		if _, err := bytesSize(typeName); err != nil {
+			return err
+		}
+		return nil
+	case strings.HasPrefix(typeName, "uint") || strings.HasPrefix(typeName, "int"):
+		if _, _, err := integerSize(typeName); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unknown type \"%s\"", typeName)
+}
+
+// encodeValue returns the 32 byte EIP-712 encoding of value as typeName.
+func (td *TypedData) encodeValue(typeName string, value interface{}) ([]byte, error) {
+	// Arrays are encoded as the hash of the concatenation of their encoded elements.
+	if match := arrayTypeRegex.FindStringSubmatch(typeName); match != nil {
+		elements, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an array for type \"%s\", got %T", typeName, value)
+		}
+		if match[2] != "" {
+			length, err := strconv.Atoi(match[2])
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing length of type \"%s\"", typeName)
+			}
+			if len(elements) != length {
+				return nil, fmt.Errorf("expected %v elements for type \"%s\", got %v", length, typeName, len(elements))
+			}
+		}
+
+		var encoded []byte
+		for i, element := range elements {
+			encodedElement, err := td.encodeValue(match[1], element)
+			if err != nil {
+				return nil, errors.Wrapf(err, "encoding element %v", i)
+			}
+			encoded = append(encoded, encodedElement...)
+		}
+		return ethcrypto.Keccak256(encoded), nil
+	}
+
+	// Structs are encoded as their hashStruct.
+	if _, ok := td.types()[typeName]; ok {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an object for type \"%s\", got %T", typeName, value)
+		}
+		return td.HashStruct(typeName, data)
+	}
+
+	return encodeAtomic(typeName, value)
+}
+
+// encodeAtomic returns the 32 byte EIP-712 encoding of value as the atomic or dynamic type typeName.
+func encodeAtomic(typeName string, value interface{}) ([]byte, error) {
+	switch typeName {
+	case "address":
+		s, ok := value.(string)
+		if !ok || !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("expected an address, got \"%v\"", value)
+		}
+		return common.LeftPadBytes(common.HexToAddress(s).Bytes(), 32), nil
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected a bool, got \"%v\"", value)
+		}
+		if b {
+			return math.U256Bytes(big.NewInt(1)), nil
+		}
+		return math.U256Bytes(big.NewInt(0)), nil
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got \"%v\"", value)
+		}
+		return ethcrypto.Keccak256([]byte(s)), nil
+	case "bytes":
+		bs, err := parseBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return ethcrypto.Keccak256(bs), nil
+	}
+
+	if strings.HasPrefix(typeName, "bytes") {
+		size, err := bytesSize(typeName)
+		if err != nil {
+			return nil, err
+		}
+		bs, err := parseBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(bs) != size {
+			return nil, fmt.Errorf("expected %v bytes for type \"%s\", got %v", size, typeName, len(bs))
+		}
+		return common.RightPadBytes(bs, 32), nil
+	}
+
+	if strings.HasPrefix(typeName, "uint") || strings.HasPrefix(typeName, "int") {
+		bits, signed, err := integerSize(typeName)
+		if err != nil {
+			return nil, err
+		}
+		n, err := parseInteger(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkIntegerRange(n, bits, signed); err != nil {
+			return nil, errors.Wrapf(err, "checking range of type \"%s\"", typeName)
+		}
+		// Negative integers are encoded as their 256 bit two's complement.
+		return math.U256Bytes(new(big.Int).Set(n)), nil
+	}
+
+	return nil, fmt.Errorf("unknown type \"%s\"", typeName)
+}
+
+// elementType strips every array suffix from typeName, "Person[][2]" becomes "Person".
+func elementType(typeName string) string {
+	for {
+		match := arrayTypeRegex.FindStringSubmatch(typeName)
+		if match == nil {
+			return typeName
+		}
+		typeName = match[1]
+	}
+}
+
+// bytesSize returns the size of the fixed size bytes type typeName, which must be between bytes1 and bytes32.
+func bytesSize(typeName string) (int, error) {
+	size, err := strconv.Atoi(strings.TrimPrefix(typeName, "bytes"))
+	if err != nil || size < 1 || size > 32 {
+		return 0, fmt.Errorf("invalid type \"%s\", fixed size bytes must be between bytes1 and bytes32", typeName)
+	}
+	return size, nil
+}
+
+// integerSize returns the number of bits of the integer type typeName, and whether it is signed. "uint" and "int" are aliases of "uint256" and "int256".
+func integerSize(typeName string) (int, bool, error) {
+	signed := strings.HasPrefix(typeName, "int")
+	sizeString := strings.TrimPrefix(strings.TrimPrefix(typeName, "u"), "int")
+	if sizeString == "" {
+		return 256, signed, nil
+	}
+
+	bits, err := strconv.Atoi(sizeString)
+	if err != nil || bits < 8 || bits > 256 || bits%8 != 0 {
+		return 0, false, fmt.Errorf("invalid type \"%s\", integers must be a multiple of 8 bits between 8 and 256", typeName)
+	}
+	return bits, signed, nil
+}
+
+// parseInteger parses value, either a JSON number or a decimal or "0x" prefixed hexadecimal string, into an integer.
+func parseInteger(value interface{}) (*big.Int, error) {
+	var s string
+	switch v := value.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = v
+	case float64:
+		// Only reachable for documents decoded without Parse, a float64 is only exact up to 2^53.
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return nil, fmt.Errorf("expected an integer, got \"%v\"", value)
+	}
+
+	n, ok := math.ParseBig256(s)
+	if !ok {
+		// math.ParseBig256 only handles non-negative integers.
+		n, ok = new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, fmt.Errorf("expected an integer, got \"%s\"", s)
+		}
+	}
+	return n, nil
+}
+
+// checkIntegerRange checks that n fits in an integer of bits bits.
+func checkIntegerRange(n *big.Int, bits int, signed bool) error {
+	if !signed {
+		if n.Sign() < 0 || n.BitLen() > bits {
+			return fmt.Errorf("%v does not fit in %v unsigned bits", n, bits)
+		}
+		return nil
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	min := new(big.Int).Neg(limit)
+	if n.Cmp(min) < 0 || n.Cmp(limit) >= 0 {
+		return fmt.Errorf("%v does not fit in %v signed bits", n, bits)
+	}
+	return nil
+}
+
+// parseBytes parses value, a "0x" prefixed hexadecimal string, into bytes.
+func parseBytes(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected \"0x\" prefixed hexadecimal bytes, got \"%v\"", value)
+	}
+
+	bs, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding \"%s\"", s)
+	}
+	return bs, nil
+}
@@ -0,0 +1,198 @@
+package eip712
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"testing"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These are the reference values of the Mail example in the EIP, signed by the private key keccak256("cow").
+const (
+	mailDomainSeparatorHex = "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"
+	mailMessageHashHex     = "c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e"
+	mailDigestHex          = "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"
+	mailSignatureHex       = "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+	mailSignerAddress      = "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"
+)
+
+func loadMail(t *testing.T) *TypedData {
+	bs, err := ioutil.ReadFile("testdata/mail.json")
+	require.NoError(t, err)
+
+	td, err := Parse(bs)
+	require.NoError(t, err)
+
+	return td
+}
+
+func Test_TypedData_EncodeType(t *testing.T) {
+	td := loadMail(t)
+
+	encodedType, err := td.EncodeType("Mail")
+	require.NoError(t, err)
+	assert.Equal(t, "Mail(Person from,Person to,string contents)Person(string name,address wallet)", encodedType)
+
+	typeHash, err := td.TypeHash("Mail")
+	require.NoError(t, err)
+	assert.Equal(t, "a0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2", hex.EncodeToString(typeHash))
+}
+
+func Test_TypedData_Digest(t *testing.T) {
+	td := loadMail(t)
+
+	domainSeparator, err := td.DomainSeparator()
+	require.NoError(t, err)
+	assert.Equal(t, mailDomainSeparatorHex, hex.EncodeToString(domainSeparator))
+
+	messageHash, err := td.MessageHash()
+	require.NoError(t, err)
+	assert.Equal(t, mailMessageHashHex, hex.EncodeToString(messageHash))
+
+	digest, err := td.Digest()
+	require.NoError(t, err)
+	assert.Equal(t, mailDigestHex, hex.EncodeToString(digest))
+}
+
+func Test_TypedData_Digest_InferredDomainType(t *testing.T) {
+	td := loadMail(t)
+	delete(td.Types, DomainType)
+
+	digest, err := td.Digest()
+	require.NoError(t, err)
+
+	assert.Equal(t, mailDigestHex, hex.EncodeToString(digest))
+}
+
+func Test_Sign(t *testing.T) {
+	td := loadMail(t)
+	w := wallet.MustFromPrivateKeyHex(hex.EncodeToString(ethcrypto.Keccak256([]byte("cow"))))
+	require.Equal(t, mailSignerAddress, w.Address())
+
+	signature, err := Sign(w, td)
+	require.NoError(t, err)
+
+	assert.Equal(t, mailSignatureHex, hex.EncodeToString(signature))
+}
+
+func Test_RecoverSigner(t *testing.T) {
+	td := loadMail(t)
+	signature, err := hex.DecodeString(mailSignatureHex)
+	require.NoError(t, err)
+
+	signer, err := RecoverSigner(td, signature)
+	require.NoError(t, err)
+	assert.Equal(t, mailSignerAddress, signer)
+
+	assert.NoError(t, Verify(mailSignerAddress, td, signature))
+
+	td.Message["contents"] = "Hello, Alice!"
+	err = Verify(mailSignerAddress, td, signature)
+	assert.True(t, errors.Is(err, wallet.ErrSignerMismatch), "expected %v, got %v", wallet.ErrSignerMismatch, err)
+}
+
+func Test_TypedData_HashStruct_Arrays(t *testing.T) {
+	td, err := Parse([]byte(`{
+		"types": {
+			"Person": [{"name": "name", "type": "string"}, {"name": "wallets", "type": "address[]"}],
+			"Group": [{"name": "name", "type": "string"}, {"name": "members", "type": "Person[2]"}]
+		},
+		"primaryType": "Group",
+		"domain": {"name": "Groups"},
+		"message": {
+			"name": "Farm",
+			"members": [
+				{"name": "Cow", "wallets": ["0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"]},
+				{"name": "Bob", "wallets": ["0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB", "0xB0BdaBea57B0BDABeA57b0bdABEA57b0BDabEa57"]}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+
+	encodedType, err := td.EncodeType("Group")
+	require.NoError(t, err)
+	assert.Equal(t, "Group(string name,Person[2] members)Person(string name,address[] wallets)", encodedType)
+
+	// An array of structs is the hash of its elements' hashStructs concatenated.
+	members := td.Message["members"].([]interface{})
+	cow, err := td.HashStruct("Person", members[0].(map[string]interface{}))
+	require.NoError(t, err)
+	bob, err := td.HashStruct("Person", members[1].(map[string]interface{}))
+	require.NoError(t, err)
+	groupTypeHash, err := td.TypeHash("Group")
+	require.NoError(t, err)
+	expected := ethcrypto.Keccak256(groupTypeHash, ethcrypto.Keccak256([]byte("Farm")), ethcrypto.Keccak256(cow, bob))
+
+	hash, err := td.MessageHash()
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(expected), hex.EncodeToString(hash))
+
+	// Fixed size arrays must have exactly their size.
+	td.Message["members"] = members[:1]
+	_, err = td.MessageHash()
+	assert.Error(t, err)
+}
+
+func Test_encodeAtomic(t *testing.T) {
+	tests := []struct {
+		name     string
+		typeName string
+		value    interface{}
+		expected string
+		err      bool
+	}{
+		{name: "uint256 decimal string", typeName: "uint256", value: "1000000000000000000000", expected: "00000000000000000000000000000000000000000000003635c9adc5dea00000"},
+		{name: "uint256 hex string", typeName: "uint256", value: "0x3635c9adc5dea00000", expected: "00000000000000000000000000000000000000000000003635c9adc5dea00000"},
+		{name: "int8 negative", typeName: "int8", value: "-1", expected: "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"},
+		{name: "int8 too small", typeName: "int8", value: "-129", err: true},
+		{name: "uint8 too large", typeName: "uint8", value: "256", err: true},
+		{name: "uint negative", typeName: "uint", value: "-1", err: true},
+		{name: "bytes4", typeName: "bytes4", value: "0x01020304", expected: "0102030400000000000000000000000000000000000000000000000000000000"},
+		{name: "bytes4 wrong size", typeName: "bytes4", value: "0x010203", err: true},
+		{name: "bool", typeName: "bool", value: true, expected: "0000000000000000000000000000000000000000000000000000000000000001"},
+		{name: "address", typeName: "address", value: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC", expected: "000000000000000000000000cccccccccccccccccccccccccccccccccccccccc"},
+		{name: "address invalid", typeName: "address", value: "0xCcCC", err: true},
+		{name: "unknown", typeName: "uint7", value: "1", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := encodeAtomic(test.typeName, test.value)
+
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, hex.EncodeToString(encoded))
+		})
+	}
+}
+
+func Test_Parse_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+	}{
+		{name: "not json", document: `{`},
+		{name: "missing primary type", document: `{"types": {"A": []}, "domain": {}, "message": {}}`},
+		{name: "undeclared primary type", document: `{"types": {"A": []}, "primaryType": "B", "domain": {}, "message": {}}`},
+		{name: "missing domain", document: `{"types": {"A": []}, "primaryType": "A", "message": {}}`},
+		{name: "unknown field type", document: `{"types": {"A": [{"name": "x", "type": "Bogus"}]}, "primaryType": "A", "domain": {}, "message": {}}`},
+		{name: "duplicate field", document: `{"types": {"A": [{"name": "x", "type": "bool"}, {"name": "x", "type": "bool"}]}, "primaryType": "A", "domain": {}, "message": {}}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse([]byte(test.document))
+
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package erc20
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jeth/pkg/abi"
+)
+
+// abiJSON is the part of the ERC-20 ABI jeth uses.
+const abiJSON = `[
+	{"type": "function", "name": "decimals", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "uint8"}]},
+	{"type": "function", "name": "symbol", "stateMutability": "view", "inputs": [], "outputs": [{"name": "", "type": "string"}]},
+	{"type": "function", "name": "balanceOf", "stateMutability": "view", "inputs": [{"name": "owner", "type": "address"}], "outputs": [{"name": "", "type": "uint256"}]},
+	{"type": "function", "name": "transfer", "stateMutability": "nonpayable", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [{"name": "", "type": "bool"}]}
+]`
+
+// ABI is the part of the ERC-20 ABI jeth uses: decimals, symbol, balanceOf, and transfer.
+var ABI = abi.MustParse(abiJSON)
+
+// Decimals calls token's decimals(), the number of decimal places its amounts are expressed in.
+func Decimals(ctx context.Context, caller ethereum.ContractCaller, token common.Address) (uint8, error) {
+	out, err := call(ctx, caller, token, "decimals")
+	if err != nil {
+		return 0, err
+	}
+
+	var decimals uint8
+	if err := ABI.UnpackIntoInterface(&decimals, "decimals", out); err != nil {
+		return 0, errors.Wrap(err, "unpacking decimals")
+	}
+	return decimals, nil
+}
+
+// Symbol calls token's symbol(). Some early tokens, such as MKR, return a bytes32 rather than a string, which is decoded too.
+func Symbol(ctx context.Context, caller ethereum.ContractCaller, token common.Address) (string, error) {
+	out, err := call(ctx, caller, token, "symbol")
+	if err != nil {
+		return "", err
+	}
+
+	if len(out) == common.HashLength {
+		return string(bytes.TrimRight(out, "\x00")), nil
+	}
+	var symbol string
+	if err := ABI.UnpackIntoInterface(&symbol, "symbol", out); err != nil {
+		return "", errors.Wrap(err, "unpacking symbol")
+	}
+	return symbol, nil
+}
+
+// BalanceOf calls token's balanceOf(owner), owner's balance in the token's smallest unit.
+func BalanceOf(ctx context.Context, caller ethereum.ContractCaller, token, owner common.Address) (*big.Int, error) {
+	out, err := call(ctx, caller, token, "balanceOf", owner)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := new(big.Int)
+	if err := ABI.UnpackIntoInterface(&balance, "balanceOf", out); err != nil {
+		return nil, errors.Wrap(err, "unpacking balance")
+	}
+	return balance, nil
+}
+
+// EncodeTransfer returns the calldata of transfer(to, amount), amount in the token's smallest unit.
+func EncodeTransfer(to common.Address, amount *big.Int) ([]byte, error) {
+	data, err := ABI.Pack("transfer", to, amount)
+	if err != nil {
+		return nil, errors.Wrap(err, "packing transfer")
+	}
+	return data, nil
+}
+
+// ParseUnits parses s, a non-negative decimal amount such as "12.5", into the token's smallest unit given its decimals.
+// It is exact, unlike parsing into a float, and it is an error for s to have more decimal places than the token.
+func ParseUnits(s string, decimals uint8) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	whole, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, fraction = s[:i], s[i+1:]
+	}
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return nil, fmt.Errorf("amount \"%s\" is not a non-negative decimal number", s)
+	}
+	if len(fraction) > int(decimals) {
+		return nil, fmt.Errorf("amount \"%s\" has more than the %v decimal places the token has", s, decimals)
+	}
+
+	amount, _ := new(big.Int).SetString(whole+fraction+strings.Repeat("0", int(decimals)-len(fraction)), 10)
+	return amount, nil
+}
+
+// FormatUnits formats amount, in the token's smallest unit, as a decimal amount given the token's decimals, without trailing zeros.
+func FormatUnits(amount *big.Int, decimals uint8) string {
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, fraction := new(big.Int).QuoRem(new(big.Int).Abs(amount), unit, new(big.Int))
+
+	s := whole.String()
+	if fraction.Sign() != 0 {
+		digits := fmt.Sprintf("%0*s", int(decimals), fraction.String())
+		s += "." + strings.TrimRight(digits, "0")
+	}
+	if amount.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// call calls method of token with args against the latest state.
+func call(ctx context.Context, caller ethereum.ContractCaller, token common.Address, method string, args ...interface{}) ([]byte, error) {
+	out, err := abi.Call(ctx, caller, ABI, token, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	// An account without code returns nothing for every call rather than failing.
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%s of %s returned nothing, it is most likely not an ERC-20 token contract", method, token.Hex())
+	}
+	return out, nil
+}
+
+// isDigits reports whether s consists of only decimal digits.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
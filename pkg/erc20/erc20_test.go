@@ -0,0 +1,176 @@
+package erc20
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Insulince/jeth/pkg/abi/abitest"
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+)
+
+var (
+	token = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	owner = common.HexToAddress(wallettest.Address)
+)
+
+func Test_Decimals(t *testing.T) {
+	caller := abitest.FakeCaller{"0x313ce567": abitest.Word("06")}
+
+	decimals, err := Decimals(context.Background(), caller, token)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(6), decimals)
+}
+
+func Test_Symbol(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		out := append(append(abitest.Word("20"), abitest.Word("04")...), common.RightPadBytes([]byte("USDC"), 32)...)
+		symbol, err := Symbol(context.Background(), abitest.FakeCaller{"0x95d89b41": out}, token)
+		require.NoError(t, err)
+		assert.Equal(t, "USDC", symbol)
+	})
+
+	t.Run("bytes32", func(t *testing.T) {
+		out := common.RightPadBytes([]byte("MKR"), 32)
+		symbol, err := Symbol(context.Background(), abitest.FakeCaller{"0x95d89b41": out}, token)
+		require.NoError(t, err)
+		assert.Equal(t, "MKR", symbol)
+	})
+}
+
+func Test_BalanceOf(t *testing.T) {
+	caller := abitest.FakeCaller{"0x70a08231": abitest.Word("3b9aca00")}
+
+	balance, err := BalanceOf(context.Background(), caller, token, owner)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000000000), balance)
+}
+
+func Test_call_NotAContract(t *testing.T) {
+	_, err := Decimals(context.Background(), abitest.FakeCaller{}, token)
+	assert.Error(t, err)
+}
+
+func Test_EncodeTransfer(t *testing.T) {
+	data, err := EncodeTransfer(owner, big.NewInt(1000000))
+	require.NoError(t, err)
+	assert.Equal(t, "0xa9059cbb"+
+		"00000000000000000000000019325d2d5c17af1096d28a12850d27bd182612f6"+
+		"00000000000000000000000000000000000000000000000000000000000f4240", hexutil.Encode(data))
+}
+
+func Test_ParseUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		decimals uint8
+		expected string
+		err      bool
+	}{
+		{
+			name:     "whole",
+			s:        "12",
+			decimals: 6,
+			expected: "12000000",
+		},
+		{
+			name:     "fraction",
+			s:        "12.5",
+			decimals: 6,
+			expected: "12500000",
+		},
+		{
+			name:     "smallest unit",
+			s:        "0.000001",
+			decimals: 6,
+			expected: "1",
+		},
+		{
+			name:     "leading dot",
+			s:        ".25",
+			decimals: 2,
+			expected: "25",
+		},
+		{
+			name:     "no decimals",
+			s:        "7",
+			decimals: 0,
+			expected: "7",
+		},
+		{
+			name:     "exact beyond float precision",
+			s:        "123456789.123456789123456789",
+			decimals: 18,
+			expected: "123456789123456789123456789",
+		},
+		{
+			name:     "too many decimal places",
+			s:        "0.0000001",
+			decimals: 6,
+			err:      true,
+		},
+		{
+			name:     "negative",
+			s:        "-1",
+			decimals: 6,
+			err:      true,
+		},
+		{
+			name:     "not a number",
+			s:        "1e6",
+			decimals: 6,
+			err:      true,
+		},
+		{
+			name:     "blank",
+			s:        "",
+			decimals: 6,
+			err:      true,
+		},
+		{
+			name:     "dot",
+			s:        ".",
+			decimals: 6,
+			err:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			amount, err := ParseUnits(test.s, test.decimals)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, amount.String())
+		})
+	}
+}
+
+func Test_FormatUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		decimals uint8
+		expected string
+	}{
+		{name: "whole", amount: 12000000, decimals: 6, expected: "12"},
+		{name: "fraction", amount: 12500000, decimals: 6, expected: "12.5"},
+		{name: "smallest unit", amount: 1, decimals: 6, expected: "0.000001"},
+		{name: "zero", amount: 0, decimals: 18, expected: "0"},
+		{name: "no decimals", amount: 7, decimals: 0, expected: "7"},
+		{name: "negative", amount: -1500, decimals: 3, expected: "-1.5"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, FormatUnits(big.NewInt(test.amount), test.decimals))
+		})
+	}
+}
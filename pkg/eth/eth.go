@@ -2,22 +2,27 @@ package eth
 
 import (
 	"math/big"
+	"strings"
 )
 
 const (
 	DefaultGateway = "https://cloudflare-eth.com"
+
+	// obfuscateVisible is the number of trailing characters ObfuscateKey leaves visible, enough to tell keys apart at a glance.
+	obfuscateVisible = 4
+	// obfuscateMinLength is the shortest key ObfuscateKey leaves any characters of visible, so never more than a quarter of a key is revealed.
+	obfuscateMinLength = 4 * obfuscateVisible
 )
 
 var (
 	LatestBlock *big.Int = nil
 )
 
+// ObfuscateKey replaces all but the last 4 characters of key with "*" so it can be displayed or logged.
+// Keys shorter than 16 characters are replaced entirely, revealing 4 characters of them would reveal too much of the key.
 func ObfuscateKey(key string) string {
-	out := ""
-	numStars := len(key[:len(key)-4])
-	for i := 0; i < numStars; i++ {
-		out += "*"
+	if len(key) < obfuscateMinLength {
+		return strings.Repeat("*", len(key))
 	}
-	out += key[len(key)-4:]
-	return out
+	return strings.Repeat("*", len(key)-obfuscateVisible) + key[len(key)-obfuscateVisible:]
 }
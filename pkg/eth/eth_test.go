@@ -0,0 +1,108 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+)
+
+func Test_ObfuscateKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		expected string
+	}{
+		{
+			name:     "private key",
+			key:      wallettest.PrivateKeyHex,
+			expected: "************************************************************03ed",
+		},
+		{
+			name:     "shortest partly visible",
+			key:      "0123456789abcdef",
+			expected: "************cdef",
+		},
+		{
+			name:     "too short to reveal any",
+			key:      "0123456789abcde",
+			expected: "***************",
+		},
+		{
+			name:     "shorter than visible",
+			key:      "abc",
+			expected: "***",
+		},
+		{
+			name:     "blank",
+			key:      "",
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ObfuscateKey(test.key))
+		})
+	}
+}
+
+func Test_SweepValue(t *testing.T) {
+	value, err := SweepValue(big.NewInt(1000000), big.NewInt(10), 21000)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(790000), value)
+
+	// The value and the fee must add up to exactly the balance, leaving nothing behind.
+	fee := new(big.Int).Mul(big.NewInt(10), big.NewInt(21000))
+	assert.Equal(t, big.NewInt(1000000), new(big.Int).Add(value, fee))
+
+	_, err = SweepValue(big.NewInt(210000), big.NewInt(10), 21000)
+	assert.True(t, errors.Is(err, ErrInsufficientFunds))
+
+	_, err = SweepValue(big.NewInt(0), big.NewInt(10), 21000)
+	assert.True(t, errors.Is(err, ErrInsufficientFunds))
+}
+
+func Test_WithMargin(t *testing.T) {
+	tests := []struct {
+		name          string
+		gas           uint64
+		marginPercent uint64
+		expected      uint64
+	}{
+		{
+			name:          "no margin",
+			gas:           21000,
+			marginPercent: 0,
+			expected:      21000,
+		},
+		{
+			name:          "exact",
+			gas:           50000,
+			marginPercent: 20,
+			expected:      60000,
+		},
+		{
+			name:          "rounds up",
+			gas:           21001,
+			marginPercent: 10,
+			expected:      23102,
+		},
+		{
+			name:          "doubled",
+			gas:           30000,
+			marginPercent: 100,
+			expected:      60000,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, WithMargin(test.gas, test.marginPercent))
+		})
+	}
+}
@@ -0,0 +1,6 @@
+package eth
+
+// WithMargin returns gas increased by marginPercent percent, rounded up, for a gas limit which still suffices if an estimate of gas turns out slightly short.
+func WithMargin(gas, marginPercent uint64) uint64 {
+	return gas + (gas*marginPercent+99)/100
+}
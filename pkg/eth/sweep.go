@@ -0,0 +1,24 @@
+package eth
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInsufficientFunds means a balance cannot cover the fee of the transaction which would spend it.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+)
+
+// SweepValue returns the value a transaction paying gasPrice per unit of gas, up to gasLimit, must send to spend balance entirely.
+// A transfer to an account without code uses exactly its gas limit of 21000, so with a legacy transaction, whose fee is always its gas price times the gas used,
+// the fee is known exactly in advance and nothing is left behind. If balance does not exceed the fee the returned error wraps ErrInsufficientFunds.
+func SweepValue(balance, gasPrice *big.Int, gasLimit uint64) (*big.Int, error) {
+	fee := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit))
+	if balance.Cmp(fee) <= 0 {
+		return nil, errors.Wrapf(ErrInsufficientFunds, "balance of %s wei cannot cover the fee of %s wei", balance, fee)
+	}
+
+	return new(big.Int).Sub(balance, fee), nil
+}
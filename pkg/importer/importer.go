@@ -0,0 +1,226 @@
+package importer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/pkg/errors"
+)
+
+// Format is the kind of file a private key was found in.
+type Format string
+
+const (
+	// FormatKeystore is a version 3 Web3 Secret Storage (keystore) file, as geth keeps in its keystore/ directory.
+	FormatKeystore Format = "keystore"
+	// FormatPresale is a 2014 Ether presale wallet file.
+	FormatPresale Format = "presale"
+	// FormatRawKey is a file holding nothing but a hexadecimal private key, optionally "0x" prefixed, as geth's "account import" reads.
+	FormatRawKey Format = "raw-key"
+
+	// maxFileSize is the largest file Scan looks inside of, key files are tiny so anything larger is skipped without reading it.
+	maxFileSize = 64 * 1024
+)
+
+// Candidate is a file which Scan recognized as holding a private key.
+type Candidate struct {
+	Path   string
+	Format Format
+	// Address is the address the file claims to hold. For encrypted formats it is only a claim until the file is decrypted, and it is the zero address if the file does not record one.
+	Address wallet.Address
+}
+
+// NeedsPassphrase reports whether c is encrypted.
+func (c Candidate) NeedsPassphrase() bool {
+	return c.Format != FormatRawKey
+}
+
+// Result is a Candidate which has been imported.
+type Result struct {
+	Candidate
+	Wallet *wallet.Wallet
+	// DuplicateOf is the path of the earlier Result which holds the same private key, or "" if c is the first.
+	DuplicateOf string
+}
+
+// Scan walks root, a file or a directory such as geth's keystore/, and returns every file in it holding a private key in a Format it recognizes, sorted by path.
+// Files it does not recognize are skipped, so root can be any directory of old keys. The files are only recognized, not decrypted, see Importer.Import.
+func Scan(root string) ([]Candidate, error) {
+	var candidates []Candidate
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || info.Size() > maxFileSize {
+			return nil
+		}
+
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "reading \"%s\"", path)
+		}
+		if format, address, ok := Detect(b); ok {
+			candidates = append(candidates, Candidate{Path: path, Format: format, Address: address})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "walking \"%s\"", root)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Path < candidates[j].Path })
+	return candidates, nil
+}
+
+// Detect reports which Format the contents b of a file are in, if any, and the address it claims to hold.
+func Detect(b []byte) (Format, wallet.Address, bool) {
+	if privateKeyHex, ok := rawKeyHex(b); ok {
+		w, err := wallet.FromPrivateKeyHex(privateKeyHex)
+		if err != nil {
+			return "", wallet.Address{}, false
+		}
+		defer w.Destroy()
+		return FormatRawKey, w.Addr(), true
+	}
+
+	// Only the fields which tell the formats apart are needed, json matches them case-insensitively, which old geth keystores ("Crypto") rely on.
+	var fields struct {
+		Crypto  json.RawMessage `json:"crypto"`
+		EncSeed string          `json:"encseed"`
+	}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return "", wallet.Address{}, false
+	}
+	switch {
+	case fields.Crypto != nil:
+		// A keystore which does not record its address is still a keystore, its address is learned once it is decrypted.
+		address, _ := wallet.KeystoreAddress(b)
+		return FormatKeystore, address, true
+	case fields.EncSeed != "":
+		address, err := wallet.PresaleAddress(b)
+		if err != nil {
+			return "", wallet.Address{}, false
+		}
+		return FormatPresale, address, true
+	}
+
+	return "", wallet.Address{}, false
+}
+
+// Decrypt reads c's file and decrypts it with passphrase, which is ignored for FormatRawKey, returning the validated Wallet inside.
+func Decrypt(c Candidate, passphrase string) (*wallet.Wallet, error) {
+	b, err := ioutil.ReadFile(c.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading \"%s\"", c.Path)
+	}
+
+	var w *wallet.Wallet
+	switch c.Format {
+	case FormatKeystore:
+		w, err = wallet.FromKeystore(b, passphrase)
+	case FormatPresale:
+		w, err = wallet.FromPresale(b, passphrase)
+	case FormatRawKey:
+		privateKeyHex, ok := rawKeyHex(b)
+		if !ok {
+			return nil, fmt.Errorf("\"%s\" does not hold a raw private key", c.Path)
+		}
+		w, err = wallet.FromPrivateKeyHex(privateKeyHex)
+	default:
+		return nil, fmt.Errorf("unknown format \"%s\"", c.Format)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "decrypting %s \"%s\"", c.Format, c.Path)
+	}
+
+	if err := w.Validate(); err != nil {
+		w.Destroy()
+		return nil, errors.Wrapf(err, "validating wallet from \"%s\"", c.Path)
+	}
+	if !c.Address.IsZero() && c.Address != w.Addr() {
+		w.Destroy()
+		return nil, errors.Wrapf(wallet.ErrAddressMismatch, "\"%s\" claims %s but holds %s", c.Path, c.Address, w.Addr())
+	}
+
+	return w, nil
+}
+
+// Importer imports Candidates, keeping track of which private keys it has already seen so duplicates are reported.
+// The same key often turns up several times when migrating, such as a keystore file and a backup copy of it, or a presale wallet which was later imported into geth.
+type Importer struct {
+	results   []Result
+	byAddress map[wallet.Address]string
+}
+
+// New creates a new empty Importer.
+func New() *Importer {
+	return &Importer{
+		byAddress: make(map[wallet.Address]string),
+	}
+}
+
+// Import decrypts c with passphrase, see Decrypt, and records the result.
+func (im *Importer) Import(c Candidate, passphrase string) (Result, error) {
+	w, err := Decrypt(c, passphrase)
+	if err != nil {
+		return Result{}, err
+	}
+
+	c.Address = w.Addr()
+	r := Result{
+		Candidate:   c,
+		Wallet:      w,
+		DuplicateOf: im.byAddress[c.Address],
+	}
+	if r.DuplicateOf == "" {
+		im.byAddress[c.Address] = c.Path
+	}
+	im.results = append(im.results, r)
+
+	return r, nil
+}
+
+// Results returns everything imported so far, in the order it was imported, duplicates included.
+func (im *Importer) Results() []Result {
+	results := make([]Result, len(im.results))
+	copy(results, im.results)
+	return results
+}
+
+// Unique returns everything imported so far except duplicates, in the order it was imported.
+func (im *Importer) Unique() []Result {
+	var unique []Result
+	for _, r := range im.results {
+		if r.DuplicateOf == "" {
+			unique = append(unique, r)
+		}
+	}
+	return unique
+}
+
+// Destroy wipes the private key of every Wallet imported so far, see wallet.Wallet.Destroy.
+func (im *Importer) Destroy() {
+	for _, r := range im.results {
+		r.Wallet.Destroy()
+	}
+}
+
+// rawKeyHex returns the private key a raw key file holds, if b is one.
+func rawKeyHex(b []byte) (string, bool) {
+	s := strings.TrimPrefix(strings.TrimSpace(string(b)), "0x")
+	if len(s) != 64 {
+		return "", false
+	}
+	if _, err := hex.DecodeString(s); err != nil {
+		return "", false
+	}
+	return strings.ToLower(s), true
+}
@@ -0,0 +1,146 @@
+package importer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+
+	// presalePath is the presale wallet pkg/wallet tests with, it decrypts with presalePassphrase.
+	presalePath       = "../wallet/testdata/presale.json"
+	presalePassphrase = "foo"
+	presaleAddress    = "0xd4584b5f6229b7be90727b0fc8c6b91bb427821f"
+)
+
+// newTestDir returns a directory laid out like a pile of old keys, and a function to remove it.
+// It holds the test wallet as a keystore file and, in a nested directory, again as a raw key file, the presale wallet, and files which hold no key.
+func newTestDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "jeth-importer")
+	require.NoError(t, err)
+
+	w := wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+	_, err = w.WriteKeystoreFile(filepath.Join(dir, "keystore"), "passphrase", wallet.KeystoreOptions{KDF: wallet.KDFScrypt, ScryptN: 1 << 4, ScryptP: 1})
+	require.NoError(t, err)
+
+	presaleBytes, err := ioutil.ReadFile(presalePath)
+	require.NoError(t, err)
+
+	files := map[string]string{
+		"backup/key.txt":      "0x" + wallettest.PrivateKeyHex + "\n",
+		"backup/presale.json": string(presaleBytes),
+		"backup/notes.txt":    "remember to migrate these",
+		"backup/other.json":   `{"hello": "world"}`,
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+	}
+
+	return dir, func() { _ = os.RemoveAll(dir) }
+}
+
+func Test_Scan(t *testing.T) {
+	dir, cleanup := newTestDir(t)
+	defer cleanup()
+
+	candidates, err := Scan(dir)
+	require.NoError(t, err)
+	require.Len(t, candidates, 3)
+
+	assert.Equal(t, filepath.Join(dir, "backup", "key.txt"), candidates[0].Path)
+	assert.Equal(t, FormatRawKey, candidates[0].Format)
+	assert.Equal(t, wallettest.Address, candidates[0].Address.Hex())
+	assert.False(t, candidates[0].NeedsPassphrase())
+
+	assert.Equal(t, FormatPresale, candidates[1].Format)
+	assert.Equal(t, wallet.MustParseAddress(presaleAddress), candidates[1].Address)
+	assert.True(t, candidates[1].NeedsPassphrase())
+
+	assert.Equal(t, FormatKeystore, candidates[2].Format)
+	assert.Equal(t, wallettest.Address, candidates[2].Address.Hex())
+	assert.True(t, candidates[2].NeedsPassphrase())
+
+	_, err = Scan(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func Test_Detect(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		format   Format
+		ok       bool
+	}{
+		{name: "raw key", contents: wallettest.PrivateKeyHex, format: FormatRawKey, ok: true},
+		{name: "raw key with prefix", contents: " 0x" + wallettest.PrivateKeyHex + "\r\n", format: FormatRawKey, ok: true},
+		{name: "invalid raw key", contents: "0000000000000000000000000000000000000000000000000000000000000000"},
+		{name: "keystore without address", contents: `{"Crypto": {}, "version": 3}`, format: FormatKeystore, ok: true},
+		{name: "presale without address", contents: `{"encseed": "00"}`},
+		{name: "other json", contents: `{"hello": "world"}`},
+		{name: "text", contents: "hello world"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			format, _, ok := Detect([]byte(test.contents))
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.format, format)
+		})
+	}
+}
+
+func Test_Importer_Import(t *testing.T) {
+	dir, cleanup := newTestDir(t)
+	defer cleanup()
+
+	candidates, err := Scan(dir)
+	require.NoError(t, err)
+	require.Len(t, candidates, 3)
+
+	im := New()
+	defer im.Destroy()
+
+	r, err := im.Import(candidates[0], "")
+	require.NoError(t, err)
+	assert.Equal(t, "", r.DuplicateOf)
+	assert.Equal(t, wallettest.PrivateKeyHex, r.Wallet.PrivateKeyHex())
+
+	_, err = im.Import(candidates[1], "wrong")
+	assert.Error(t, err)
+	r, err = im.Import(candidates[1], presalePassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, "", r.DuplicateOf)
+
+	// The keystore holds the same key as the raw key file.
+	_, err = im.Import(candidates[2], "wrong")
+	assert.Error(t, err)
+	r, err = im.Import(candidates[2], "passphrase")
+	require.NoError(t, err)
+	assert.Equal(t, candidates[0].Path, r.DuplicateOf)
+
+	assert.Len(t, im.Results(), 3)
+	unique := im.Unique()
+	require.Len(t, unique, 2)
+	assert.Equal(t, candidates[0].Path, unique[0].Path)
+	assert.Equal(t, candidates[1].Path, unique[1].Path)
+}
+
+func Test_Decrypt_AddressMismatch(t *testing.T) {
+	dir, cleanup := newTestDir(t)
+	defer cleanup()
+
+	c := Candidate{Path: filepath.Join(dir, "backup", "key.txt"), Format: FormatRawKey, Address: wallet.MustParseAddress(presaleAddress)}
+
+	_, err := Decrypt(c, "")
+	assert.Error(t, err)
+}
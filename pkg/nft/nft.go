@@ -0,0 +1,193 @@
+package nft
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/Insulince/jeth/pkg/abi"
+)
+
+// Standard is the token standard an NFT contract implements.
+type Standard string
+
+const (
+	// ERC721 contracts hold one of each token id, owned by a single account.
+	ERC721 Standard = "ERC-721"
+	// ERC1155 contracts hold any amount of each token id, and may transfer several token ids at once.
+	ERC1155 Standard = "ERC-1155"
+)
+
+// ERC-165 interface ids, the XOR of the selectors of every function in the interface.
+var (
+	InterfaceERC165  = [4]byte{0x01, 0xff, 0xc9, 0xa7}
+	InterfaceERC721  = [4]byte{0x80, 0xac, 0x58, 0xcd}
+	InterfaceERC1155 = [4]byte{0xd9, 0xb6, 0x7a, 0x26}
+	// interfaceInvalid must never be supported, see Detect.
+	interfaceInvalid = [4]byte{0xff, 0xff, 0xff, 0xff}
+)
+
+// ERC165ABI is the ERC-165 ABI: supportsInterface.
+var ERC165ABI = abi.MustParse(`[
+	{"type": "function", "name": "supportsInterface", "stateMutability": "view", "inputs": [{"name": "interfaceId", "type": "bytes4"}], "outputs": [{"name": "", "type": "bool"}]}
+]`)
+
+// ERC721ABI is the part of the ERC-721 ABI jeth uses: ownerOf, and safeTransferFrom without data.
+var ERC721ABI = abi.MustParse(`[
+	{"type": "function", "name": "ownerOf", "stateMutability": "view", "inputs": [{"name": "tokenId", "type": "uint256"}], "outputs": [{"name": "", "type": "address"}]},
+	{"type": "function", "name": "safeTransferFrom", "stateMutability": "nonpayable", "inputs": [{"name": "from", "type": "address"}, {"name": "to", "type": "address"}, {"name": "tokenId", "type": "uint256"}], "outputs": []}
+]`)
+
+// ERC1155ABI is the part of the ERC-1155 ABI jeth uses: balanceOfBatch, safeTransferFrom, and safeBatchTransferFrom.
+var ERC1155ABI = abi.MustParse(`[
+	{"type": "function", "name": "balanceOfBatch", "stateMutability": "view", "inputs": [{"name": "accounts", "type": "address[]"}, {"name": "ids", "type": "uint256[]"}], "outputs": [{"name": "", "type": "uint256[]"}]},
+	{"type": "function", "name": "safeTransferFrom", "stateMutability": "nonpayable", "inputs": [{"name": "from", "type": "address"}, {"name": "to", "type": "address"}, {"name": "id", "type": "uint256"}, {"name": "amount", "type": "uint256"}, {"name": "data", "type": "bytes"}], "outputs": []},
+	{"type": "function", "name": "safeBatchTransferFrom", "stateMutability": "nonpayable", "inputs": [{"name": "from", "type": "address"}, {"name": "to", "type": "address"}, {"name": "ids", "type": "uint256[]"}, {"name": "amounts", "type": "uint256[]"}, {"name": "data", "type": "bytes"}], "outputs": []}
+]`)
+
+// SupportsInterface calls contract's supportsInterface(id). A contract returning nothing, such as an account without code, does not support it.
+func SupportsInterface(ctx context.Context, caller ethereum.ContractCaller, contract common.Address, id [4]byte) (bool, error) {
+	out, err := abi.Call(ctx, caller, ERC165ABI, contract, "supportsInterface", id)
+	if err != nil {
+		return false, err
+	}
+	if len(out) == 0 {
+		return false, nil
+	}
+
+	var supported bool
+	if err := ERC165ABI.UnpackIntoInterface(&supported, "supportsInterface", out); err != nil {
+		return false, errors.Wrap(err, "unpacking supportsInterface")
+	}
+	return supported, nil
+}
+
+// Detect detects the standard contract implements with ERC-165, as the ERC-165 specification describes: it must support ERC-165 itself and must not support the invalid interface id 0xffffffff.
+// It is an error if contract implements neither ERC-721 nor ERC-1155.
+func Detect(ctx context.Context, caller ethereum.ContractCaller, contract common.Address) (Standard, error) {
+	erc165, err := SupportsInterface(ctx, caller, contract, InterfaceERC165)
+	if err != nil {
+		return "", err
+	}
+	invalid, err := SupportsInterface(ctx, caller, contract, interfaceInvalid)
+	if err != nil {
+		return "", err
+	}
+	if !erc165 || invalid {
+		return "", fmt.Errorf("%s does not implement ERC-165, it is most likely not an NFT contract", contract.Hex())
+	}
+
+	if ok, err := SupportsInterface(ctx, caller, contract, InterfaceERC721); err != nil {
+		return "", err
+	} else if ok {
+		return ERC721, nil
+	}
+	if ok, err := SupportsInterface(ctx, caller, contract, InterfaceERC1155); err != nil {
+		return "", err
+	} else if ok {
+		return ERC1155, nil
+	}
+	return "", fmt.Errorf("%s implements neither %s nor %s", contract.Hex(), ERC721, ERC1155)
+}
+
+// OwnerOf calls the ERC-721 contract's ownerOf(id), the account which owns token id.
+func OwnerOf(ctx context.Context, caller ethereum.ContractCaller, contract common.Address, id *big.Int) (common.Address, error) {
+	out, err := abi.Call(ctx, caller, ERC721ABI, contract, "ownerOf", id)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(out) == 0 {
+		return common.Address{}, fmt.Errorf("ownerOf of %s returned nothing, it is most likely not an %s contract", contract.Hex(), ERC721)
+	}
+
+	var owner common.Address
+	if err := ERC721ABI.UnpackIntoInterface(&owner, "ownerOf", out); err != nil {
+		return common.Address{}, errors.Wrap(err, "unpacking owner")
+	}
+	return owner, nil
+}
+
+// BalanceOfBatch calls the ERC-1155 contract's balanceOfBatch for owner and each of ids, returning owner's balance of each, in the same order.
+func BalanceOfBatch(ctx context.Context, caller ethereum.ContractCaller, contract, owner common.Address, ids []*big.Int) ([]*big.Int, error) {
+	owners := make([]common.Address, len(ids))
+	for i := range owners {
+		owners[i] = owner
+	}
+	out, err := abi.Call(ctx, caller, ERC1155ABI, contract, "balanceOfBatch", owners, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("balanceOfBatch of %s returned nothing, it is most likely not an %s contract", contract.Hex(), ERC1155)
+	}
+
+	var balances []*big.Int
+	if err := ERC1155ABI.UnpackIntoInterface(&balances, "balanceOfBatch", out); err != nil {
+		return nil, errors.Wrap(err, "unpacking balances")
+	}
+	if len(balances) != len(ids) {
+		return nil, fmt.Errorf("balanceOfBatch of %s returned %v balances for %v token ids", contract.Hex(), len(balances), len(ids))
+	}
+	return balances, nil
+}
+
+// EncodeERC721Transfer returns the calldata of the ERC-721 safeTransferFrom(from, to, id).
+func EncodeERC721Transfer(from, to common.Address, id *big.Int) ([]byte, error) {
+	data, err := ERC721ABI.Pack("safeTransferFrom", from, to, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "packing safeTransferFrom")
+	}
+	return data, nil
+}
+
+// EncodeERC1155Transfer returns the calldata transferring amounts[i] of ids[i] from from to to, without data.
+// A single token id is transferred with safeTransferFrom, several at once with safeBatchTransferFrom.
+func EncodeERC1155Transfer(from, to common.Address, ids, amounts []*big.Int) ([]byte, error) {
+	if len(ids) == 0 || len(ids) != len(amounts) {
+		return nil, fmt.Errorf("must transfer an amount of each token id, got %v token ids and %v amounts", len(ids), len(amounts))
+	}
+
+	if len(ids) == 1 {
+		data, err := ERC1155ABI.Pack("safeTransferFrom", from, to, ids[0], amounts[0], []byte{})
+		if err != nil {
+			return nil, errors.Wrap(err, "packing safeTransferFrom")
+		}
+		return data, nil
+	}
+	data, err := ERC1155ABI.Pack("safeBatchTransferFrom", from, to, ids, amounts, []byte{})
+	if err != nil {
+		return nil, errors.Wrap(err, "packing safeBatchTransferFrom")
+	}
+	return data, nil
+}
+
+// ParseTokenID parses s, a token id as a uint256, either in decimal, where leading zeros do not make it octal, or in hexadecimal with an explicit "0x" prefix.
+// Unlike big.Int's base 0 parsing, no other prefix, sign, or "_" separator is accepted, so an id is never silently read in a base it was not meant in.
+func ParseTokenID(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	digits, base := s, 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		digits, base = s[2:], 16
+	}
+	valid := digits != ""
+	for _, r := range digits {
+		if !('0' <= r && r <= '9' || base == 16 && ('a' <= r && r <= 'f' || 'A' <= r && r <= 'F')) {
+			valid = false
+			break
+		}
+	}
+	if !valid {
+		return nil, fmt.Errorf("token id \"%s\" is neither a decimal integer nor a hexadecimal integer prefixed with \"0x\"", s)
+	}
+
+	id, _ := new(big.Int).SetString(digits, base)
+	if id.BitLen() > 256 {
+		return nil, fmt.Errorf("token id \"%s\" does not fit in a uint256", s)
+	}
+	return id, nil
+}
@@ -0,0 +1,211 @@
+package nft
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Insulince/jeth/pkg/abi/abitest"
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+)
+
+var (
+	contract = common.HexToAddress("0xBC4CA0EdA7647A8aB7C2061c2E118A18a936f13D")
+	owner    = common.HexToAddress(wallettest.Address)
+	receiver = common.HexToAddress("0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F")
+)
+
+// supports returns the calldata of supportsInterface(id), as an abitest.FakeCaller key.
+func supports(id [4]byte) string {
+	return hexutil.Encode(append([]byte{0x01, 0xff, 0xc9, 0xa7}, common.RightPadBytes(id[:], 32)...))
+}
+
+func Test_Detect(t *testing.T) {
+	yes, no := abitest.Word("01"), abitest.Word("00")
+
+	tests := []struct {
+		name     string
+		caller   abitest.FakeCaller
+		expected Standard
+		err      bool
+	}{
+		{
+			name:     "erc721",
+			caller:   abitest.FakeCaller{supports(InterfaceERC165): yes, supports(interfaceInvalid): no, supports(InterfaceERC721): yes, supports(InterfaceERC1155): no},
+			expected: ERC721,
+		},
+		{
+			name:     "erc1155",
+			caller:   abitest.FakeCaller{supports(InterfaceERC165): yes, supports(interfaceInvalid): no, supports(InterfaceERC721): no, supports(InterfaceERC1155): yes},
+			expected: ERC1155,
+		},
+		{
+			name:   "neither",
+			caller: abitest.FakeCaller{supports(InterfaceERC165): yes, supports(interfaceInvalid): no, supports(InterfaceERC721): no, supports(InterfaceERC1155): no},
+			err:    true,
+		},
+		{
+			name:   "supports the invalid interface",
+			caller: abitest.FakeCaller{supports(InterfaceERC165): yes, supports(interfaceInvalid): yes, supports(InterfaceERC721): yes, supports(InterfaceERC1155): yes},
+			err:    true,
+		},
+		{
+			name:   "not a contract",
+			caller: abitest.FakeCaller{},
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			standard, err := Detect(context.Background(), test.caller, contract)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, standard)
+		})
+	}
+}
+
+func Test_OwnerOf(t *testing.T) {
+	caller := abitest.FakeCaller{"0x6352211e": abitest.Word(owner.Hex()[2:])}
+
+	actual, err := OwnerOf(context.Background(), caller, contract, big.NewInt(42))
+	require.NoError(t, err)
+	assert.Equal(t, owner, actual)
+
+	_, err = OwnerOf(context.Background(), abitest.FakeCaller{}, contract, big.NewInt(42))
+	assert.Error(t, err)
+}
+
+func Test_BalanceOfBatch(t *testing.T) {
+	out := append(append(append(abitest.Word("20"), abitest.Word("02")...), abitest.Word("05")...), abitest.Word("00")...)
+	caller := abitest.FakeCaller{"0x4e1273f4": out}
+
+	balances, err := BalanceOfBatch(context.Background(), caller, contract, owner, []*big.Int{big.NewInt(1), big.NewInt(2)})
+	require.NoError(t, err)
+	require.Len(t, balances, 2)
+	assert.Equal(t, "5", balances[0].String())
+	assert.Equal(t, "0", balances[1].String())
+
+	_, err = BalanceOfBatch(context.Background(), caller, contract, owner, []*big.Int{big.NewInt(1)})
+	assert.Error(t, err)
+}
+
+func Test_EncodeERC721Transfer(t *testing.T) {
+	data, err := EncodeERC721Transfer(owner, receiver, big.NewInt(42))
+	require.NoError(t, err)
+	assert.Equal(t, "0x42842e0e"+
+		"00000000000000000000000019325d2d5c17af1096d28a12850d27bd182612f6"+
+		"0000000000000000000000009d8a62f656a8d1615c1294fd71e9cfb3e4855a4f"+
+		"000000000000000000000000000000000000000000000000000000000000002a", hexutil.Encode(data))
+}
+
+func Test_EncodeERC1155Transfer(t *testing.T) {
+	tests := []struct {
+		name     string
+		ids      []int64
+		amounts  []int64
+		selector string
+		err      bool
+	}{
+		{
+			name:     "single",
+			ids:      []int64{1},
+			amounts:  []int64{3},
+			selector: "0xf242432a",
+		},
+		{
+			name:     "batch",
+			ids:      []int64{1, 2},
+			amounts:  []int64{3, 1},
+			selector: "0x2eb2c2d6",
+		},
+		{
+			name:    "missing amount",
+			ids:     []int64{1, 2},
+			amounts: []int64{3},
+			err:     true,
+		},
+		{
+			name: "nothing",
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := EncodeERC1155Transfer(owner, receiver, bigs(test.ids), bigs(test.amounts))
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.selector, hexutil.Encode(data[:4]))
+
+			method, err := ERC1155ABI.MethodById(data[:4])
+			require.NoError(t, err)
+			args, err := method.Inputs.Unpack(data[4:])
+			require.NoError(t, err)
+			assert.Equal(t, owner, args[0])
+			assert.Equal(t, receiver, args[1])
+		})
+	}
+}
+
+func Test_ParseTokenID(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected string
+		err      bool
+	}{
+		{name: "decimal", s: "42", expected: "42"},
+		{name: "decimal, leading zero", s: "010", expected: "10"},
+		{name: "decimal, spaces", s: " 7 ", expected: "7"},
+		{name: "zero", s: "0", expected: "0"},
+		{name: "hexadecimal", s: "0x2a", expected: "42"},
+		{name: "hexadecimal, upper case", s: "0X2A", expected: "42"},
+		{name: "max uint256", s: "0x" + strings.Repeat("ff", 32), expected: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)).String()},
+		{name: "over uint256", s: "0x1" + strings.Repeat("00", 32), err: true},
+		{name: "hexadecimal without prefix", s: "2a", err: true},
+		{name: "octal prefix", s: "0o17", err: true},
+		{name: "binary prefix", s: "0b101", err: true},
+		{name: "separator", s: "1_000", err: true},
+		{name: "hexadecimal, separator", s: "0x1_0", err: true},
+		{name: "negative", s: "-1", err: true},
+		{name: "positive sign", s: "+1", err: true},
+		{name: "hexadecimal, sign", s: "0x-1", err: true},
+		{name: "prefix only", s: "0x", err: true},
+		{name: "blank", s: "", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			id, err := ParseTokenID(test.s)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, id.String())
+		})
+	}
+}
+
+// bigs converts ns to big.Ints.
+func bigs(ns []int64) []*big.Int {
+	bs := make([]*big.Int, len(ns))
+	for i, n := range ns {
+		bs[i] = big.NewInt(n)
+	}
+	return bs
+}
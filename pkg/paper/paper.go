@@ -0,0 +1,180 @@
+package paper
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/skip2/go-qrcode"
+)
+
+// A paper wallet is laid out once, in points on an A4 page, and drawn by both the PDF and PNG renderers.
+// Everything is generated locally, nothing is fetched, so paper wallets can be made on an air-gapped machine.
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 40.0
+	qrSize     = 180.0
+	// charWidth is the widest a character of either renderer's monospaced font is, as a fraction of the font size.
+	// Courier is 0.6, the PNG's scaled bitmap font can be a little wider.
+	charWidth = 0.65
+)
+
+var (
+	black = color.RGBA{A: 0xff}
+	grey  = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+	red   = color.RGBA{R: 0xc0, A: 0xff}
+	white = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+)
+
+// Content is what a paper wallet shows, a public half which may be shared and a secret half which must not be.
+type Content struct {
+	// Address is the wallet's address, shown on the public half.
+	Address string
+	// SecretLabel names Secret, such as "PRIVATE KEY" or "MNEMONIC".
+	SecretLabel string
+	// Secret is the private key or mnemonic, shown on the secret half.
+	Secret string
+	// Notes are extra lines shown on the secret half, such as the derivation path of a mnemonic.
+	Notes []string
+}
+
+// Validate checks that c has everything a paper wallet needs.
+func (c Content) Validate() error {
+	if !common.IsHexAddress(c.Address) {
+		return fmt.Errorf("\"%s\" is not a valid address", c.Address)
+	}
+	if c.SecretLabel == "" {
+		return errors.New("secret label must not be blank")
+	}
+	if c.Secret == "" {
+		return errors.New("secret must not be blank")
+	}
+	return nil
+}
+
+// canvas is what a paper wallet is drawn on. Coordinates are in points from the top left of the page.
+type canvas interface {
+	// fillRect fills the rectangle with its top left corner at x, y.
+	fillRect(x, y, w, h float64, c color.RGBA)
+	// strokeRect outlines the rectangle with its top left corner at x, y.
+	strokeRect(x, y, w, h, width float64, c color.RGBA)
+	// dashedLine draws a dashed horizontal line from x1 to x2 at y.
+	dashedLine(x1, x2, y float64, c color.RGBA)
+	// text draws s in a monospaced font of size points with its top left corner at x, y.
+	text(x, y, size float64, s string, c color.RGBA)
+}
+
+// layout lays content out on c.
+func layout(c canvas, content Content) error {
+	if err := content.Validate(); err != nil {
+		return errors.Wrap(err, "validating content")
+	}
+
+	addressQR, err := qrcode.New(content.Address, qrcode.Medium)
+	if err != nil {
+		return errors.Wrap(err, "encoding address qr code")
+	}
+	// The secret is encoded with the highest error correction so a worn or stained sheet still scans.
+	secretQR, err := qrcode.New(content.Secret, qrcode.High)
+	if err != nil {
+		return errors.Wrap(err, "encoding secret qr code")
+	}
+
+	c.fillRect(0, 0, pageWidth, pageHeight, white)
+	c.text(margin, margin, 20, "ETHEREUM PAPER WALLET", black)
+
+	// The public half.
+	top := 90.0
+	textX := margin + qrSize + 20
+	textWidth := pageWidth - margin - textX
+	c.text(margin, top, 12, "ADDRESS - PUBLIC - share this to receive funds", black)
+	drawQR(c, margin, top+20, qrSize, addressQR.Bitmap())
+	y := top + 40
+	for _, line := range wrap(content.Address, 11, textWidth) {
+		c.text(textX, y, 11, line, black)
+		y += 16
+	}
+
+	// The fold between the halves, so the secret half can be folded away or cut off.
+	foldY := top + qrSize + 50
+	c.dashedLine(margin, pageWidth-margin, foldY, grey)
+	c.text(margin, foldY+6, 8, "fold or cut here - keep the half below hidden", grey)
+
+	// The secret half, boxed and marked in red so it is never mistaken for the public half.
+	top = foldY + 40
+	c.text(margin, top, 12, fmt.Sprintf("%s - SECRET - NEVER SHARE OR PHOTOGRAPH", strings.ToUpper(content.SecretLabel)), red)
+	c.text(margin, top+16, 9, "anyone who sees this can take everything in this wallet", red)
+	drawQR(c, margin, top+36, qrSize, secretQR.Bitmap())
+	y = top + 56
+	for _, line := range wrap(content.Secret, 11, textWidth) {
+		c.text(textX, y, 11, line, red)
+		y += 16
+	}
+	y += 16
+	for _, note := range content.Notes {
+		for _, line := range wrap(note, 8, textWidth) {
+			c.text(textX, y, 8, line, black)
+			y += 12
+		}
+	}
+	bottom := math.Max(top+36+qrSize, y) + 10
+	c.strokeRect(margin-10, top-10, pageWidth-2*margin+20, bottom-top+10, 3, red)
+
+	return nil
+}
+
+// drawQR draws bitmap, as returned by qrcode.QRCode.Bitmap, as a size by size square with its top left corner at x, y.
+// Each horizontal run of dark modules is drawn as one rectangle, so no hairline gaps appear between neighbouring modules when a reader anti-aliases them.
+func drawQR(c canvas, x, y, size float64, bitmap [][]bool) {
+	module := size / float64(len(bitmap))
+	for row := range bitmap {
+		for col := 0; col < len(bitmap[row]); col++ {
+			if !bitmap[row][col] {
+				continue
+			}
+			start := col
+			for col < len(bitmap[row]) && bitmap[row][col] {
+				col++
+			}
+			c.fillRect(x+float64(start)*module, y+float64(row)*module, float64(col-start)*module, module, black)
+		}
+	}
+}
+
+// wrap splits s into lines which fit in width points at size points, breaking between words where it can and within them where it must.
+func wrap(s string, size, width float64) []string {
+	columns := int(width / (size * charWidth))
+	if columns < 1 {
+		columns = 1
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for len(word) > columns {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, word[:columns])
+			word = word[columns:]
+		}
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= columns:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
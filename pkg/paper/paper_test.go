@@ -0,0 +1,78 @@
+package paper
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+)
+
+var (
+	content = Content{
+		Address:     wallettest.Address,
+		SecretLabel: "private key",
+		Secret:      wallettest.PrivateKeyHex,
+		Notes:       []string{"generated by jeth"},
+	}
+)
+
+func Test_WritePNG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePNG(&buf, content))
+
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, int(pageWidth*pngScale), img.Bounds().Dx())
+	assert.Equal(t, int(pageHeight*pngScale), img.Bounds().Dy())
+}
+
+func Test_WritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePDF(&buf, content))
+	pdf := buf.String()
+
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, "("+content.Address+")")
+	assert.Contains(t, pdf, "PRIVATE KEY - SECRET")
+
+	// Every object must be where the cross-reference table says it is, or readers will have to repair the file.
+	xref := pdf[strings.LastIndex(pdf, "\nxref\n")+1:]
+	lines := strings.Split(xref, "\n")[3:8]
+	for i, line := range lines {
+		var offset int
+		_, err := fmt.Sscanf(line, "%d", &offset)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(pdf[offset:], strconv.Itoa(i+1)+" 0 obj\n"), "object %v", i+1)
+	}
+}
+
+func Test_Content_Validate(t *testing.T) {
+	assert.NoError(t, content.Validate())
+
+	invalid := content
+	invalid.Address = "0x1932"
+	assert.Error(t, invalid.Validate())
+
+	invalid = content
+	invalid.Secret = ""
+	assert.Error(t, invalid.Validate())
+}
+
+func Test_wrap(t *testing.T) {
+	// 10 points at size 10 fits 1 column, so use widths that are whole multiples of a column.
+	column := 10 * charWidth
+
+	assert.Equal(t, []string{"abandon", "ability", "able"}, wrap("abandon ability able", 10, 8*column))
+	assert.Equal(t, []string{"abandon ability", "able"}, wrap("abandon ability able", 10, 16*column))
+	assert.Equal(t, []string{"0123", "4567", "89"}, wrap("0123456789", 10, 4*column))
+	assert.Nil(t, wrap("", 10, 4*column))
+}
@@ -0,0 +1,83 @@
+package paper
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WritePDF renders a paper wallet showing content as a single page A4 PDF and writes it to w.
+// The PDF is written by hand using only the standard Courier font, which every PDF reader has built in, so nothing needs embedding or fetching.
+// QR codes are drawn as vector rectangles, so they stay sharp at any print resolution.
+func WritePDF(w io.Writer, content Content) error {
+	c := &pdfCanvas{}
+	if err := layout(c, content); err != nil {
+		return errors.Wrap(err, "drawing paper wallet")
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %v %v] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>", pageWidth, pageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %v >>\nstream\n%s\nendstream", c.content.Len(), c.content.String()),
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%v 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %v\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %v /Root 1 0 R >>\nstartxref\n%v\n%%%%EOF\n", len(objects)+1, xref)
+
+	if _, err := w.Write(pdf.Bytes()); err != nil {
+		return errors.Wrap(err, "writing pdf")
+	}
+
+	return nil
+}
+
+// pdfCanvas draws into a PDF content stream. PDF's origin is the bottom left of the page, so every y is flipped.
+type pdfCanvas struct {
+	content bytes.Buffer
+}
+
+func (c *pdfCanvas) fillRect(x, y, w, h float64, col color.RGBA) {
+	fmt.Fprintf(&c.content, "%s rg %.2f %.2f %.2f %.2f re f\n", pdfColor(col), x, pageHeight-y-h, w, h)
+}
+
+func (c *pdfCanvas) strokeRect(x, y, w, h, width float64, col color.RGBA) {
+	// PDF strokes are centered on the path, inset it so the stroke stays inside the rectangle like the PNG's does.
+	fmt.Fprintf(&c.content, "%s RG %.2f w %.2f %.2f %.2f %.2f re S\n", pdfColor(col), width, x+width/2, pageHeight-y-h+width/2, w-width, h-width)
+}
+
+func (c *pdfCanvas) dashedLine(x1, x2, y float64, col color.RGBA) {
+	fmt.Fprintf(&c.content, "%s RG 1 w [4 4] 0 d %.2f %.2f m %.2f %.2f l S [] 0 d\n", pdfColor(col), x1, pageHeight-y, x2, pageHeight-y)
+}
+
+func (c *pdfCanvas) text(x, y, size float64, s string, col color.RGBA) {
+	// The baseline sits roughly 80% of the way down a line of text.
+	fmt.Fprintf(&c.content, "BT %s rg /F1 %.2f Tf %.2f %.2f Td (%s) Tj ET\n", pdfColor(col), size, x, pageHeight-y-size*0.8, pdfEscape(s))
+}
+
+// pdfColor formats col as PDF RGB color components.
+func pdfColor(col color.RGBA) string {
+	return fmt.Sprintf("%.3f %.3f %.3f", float64(col.R)/0xff, float64(col.G)/0xff, float64(col.B)/0xff)
+}
+
+// pdfEscape escapes s for use in a PDF literal string.
+func pdfEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
@@ -0,0 +1,86 @@
+package paper
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// pngScale is the number of pixels per point, 2 makes an A4 page about 144 DPI, plenty for the QR codes to scan.
+	pngScale = 2
+)
+
+// WritePNG renders a paper wallet showing content as a PNG image of an A4 page and writes it to w.
+func WritePNG(w io.Writer, content Content) error {
+	c := &pngCanvas{img: image.NewRGBA(image.Rect(0, 0, int(pageWidth*pngScale), int(pageHeight*pngScale)))}
+	if err := layout(c, content); err != nil {
+		return errors.Wrap(err, "drawing paper wallet")
+	}
+
+	if err := png.Encode(w, c.img); err != nil {
+		return errors.Wrap(err, "encoding png")
+	}
+
+	return nil
+}
+
+// pngCanvas draws onto an image.
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+func (c *pngCanvas) fillRect(x, y, w, h float64, col color.RGBA) {
+	// Round each edge rather than the size so adjacent QR code modules never leave a gap between them.
+	r := image.Rect(px(x), px(y), px(x+w), px(y+h))
+	draw.Draw(c.img, r, image.NewUniform(col), image.Point{}, draw.Src)
+}
+
+func (c *pngCanvas) strokeRect(x, y, w, h, width float64, col color.RGBA) {
+	c.fillRect(x, y, w, width, col)
+	c.fillRect(x, y+h-width, w, width, col)
+	c.fillRect(x, y, width, h, col)
+	c.fillRect(x+w-width, y, width, h, col)
+}
+
+func (c *pngCanvas) dashedLine(x1, x2, y float64, col color.RGBA) {
+	for x := x1; x < x2; x += 8 {
+		c.fillRect(x, y, math.Min(4, x2-x), 1, col)
+	}
+}
+
+func (c *pngCanvas) text(x, y, size float64, s string, col color.RGBA) {
+	face := basicfont.Face7x13
+	metrics := face.Metrics()
+
+	// The only offline font available is a small bitmap font, so it is drawn at its native size and scaled up by whole pixels.
+	mask := image.NewAlpha(image.Rect(0, 0, font.MeasureString(face, s).Ceil(), metrics.Height.Ceil()))
+	d := font.Drawer{Dst: mask, Src: image.Opaque, Face: face, Dot: fixed.Point26_6{Y: metrics.Ascent}}
+	d.DrawString(s)
+
+	scale := int(math.Max(1, math.Round(size*pngScale/float64(metrics.Height.Ceil()))))
+	src := image.NewUniform(col)
+	b := mask.Bounds()
+	for my := b.Min.Y; my < b.Max.Y; my++ {
+		for mx := b.Min.X; mx < b.Max.X; mx++ {
+			if mask.AlphaAt(mx, my).A < 0x80 {
+				continue
+			}
+			r := image.Rect(px(x)+mx*scale, px(y)+my*scale, px(x)+(mx+1)*scale, px(y)+(my+1)*scale)
+			draw.Draw(c.img, r, src, image.Point{}, draw.Src)
+		}
+	}
+}
+
+// px converts points to pixels.
+func px(points float64) int {
+	return int(math.Round(points * pngScale))
+}
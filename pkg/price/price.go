@@ -2,15 +2,34 @@ package price
 
 import (
 	"encoding/json"
+	"fmt"
+	"math/big"
 	"net/http"
+	"net/url"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 )
 
 const (
-	coinbaseBuyPriceUrl = "https://api.coinbase.com/v2/prices/ETH-USD/buy"
+	// coinbaseBuyPriceUrlFormat is formatted with the symbol of the currency to price in dollars, such as "ETH".
+	coinbaseBuyPriceUrlFormat = "https://api.coinbase.com/v2/prices/%s-USD/buy"
 )
 
+// knownTokens are the symbols, as Coinbase lists them, of well-known ERC-20 tokens by chain id and contract address.
+// A token's own symbol is whatever its contract claims, so any contract could call itself "USDC", only these are priced.
+var knownTokens = map[uint64]map[common.Address]string{
+	// mainnet
+	1: {
+		common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"): "USDC",
+		common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"): "USDT",
+		common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"): "DAI",
+		common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"): "ETH", // WETH, redeemable 1:1 for ether.
+		common.HexToAddress("0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599"): "WBTC",
+	},
+}
+
 type (
 	coinbaseBuyPriceResponseBody struct {
 		Data struct {
@@ -22,7 +41,26 @@ type (
 )
 
 func UsdPerEth() (float64, error) {
-	req, err := http.NewRequest(http.MethodGet, coinbaseBuyPriceUrl, nil)
+	return UsdPer("ETH")
+}
+
+// UsdPerToken returns the price in dollars of one whole ERC-20 token at address on the chain chainID.
+// It is an error if the token is not one of the well-known tokens jeth prices, see knownTokens, whatever symbol it claims.
+func UsdPerToken(chainID *big.Int, address common.Address) (float64, error) {
+	var symbol string
+	if chainID.IsUint64() {
+		symbol = knownTokens[chainID.Uint64()][address]
+	}
+	if symbol == "" {
+		return 0, fmt.Errorf("%s is not a well-known token on chain id %s, its symbol is set by its own contract and cannot be trusted to price it", address.Hex(), chainID)
+	}
+	return UsdPer(symbol)
+}
+
+// UsdPer returns the price in dollars of one unit of the currency symbol, such as "USDC", as Coinbase lists it.
+// It is an error if Coinbase does not list symbol.
+func UsdPer(symbol string) (float64, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(coinbaseBuyPriceUrlFormat, url.PathEscape(strings.ToUpper(symbol))), nil)
 	if err != nil {
 		return 0, errors.Wrap(err, "building request")
 	}
@@ -32,6 +70,9 @@ func UsdPerEth() (float64, error) {
 		return 0, errors.Wrap(err, "executing request")
 	}
 	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("coinbase has no price for \"%s\", responded %s", symbol, res.Status)
+	}
 
 	var body coinbaseBuyPriceResponseBody
 	if err = json.NewDecoder(res.Body).Decode(&body); err != nil {
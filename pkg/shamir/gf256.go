@@ -0,0 +1,67 @@
+package shamir
+
+// Shamir's secret sharing is done byte by byte over GF(2^8), using the same reducing polynomial as AES, x^8 + x^4 + x^3 + x + 1.
+// In this field addition and subtraction are both XOR.
+// Multiplication is done without tables or branches on its operands so that its timing does not depend on the secret.
+
+// gfMul multiplies a and b in GF(2^8).
+func gfMul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		// Add a if the low bit of b is set.
+		p ^= a & -(b & 1)
+		// Multiply a by x, reducing by the polynomial if it overflows.
+		overflow := a >> 7
+		a <<= 1
+		a ^= 0x1b & -overflow
+		b >>= 1
+	}
+	return p
+}
+
+// gfInv returns the multiplicative inverse of a in GF(2^8), a^254. The inverse of 0 does not exist, 0 is returned for it.
+func gfInv(a byte) byte {
+	// Square and multiply, 254 = 0b11111110.
+	result := byte(1)
+	square := a
+	for e := 254; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			result = gfMul(result, square)
+		}
+		square = gfMul(square, square)
+	}
+	return result
+}
+
+// gfDiv divides a by b in GF(2^8), b must not be 0.
+func gfDiv(a, b byte) byte {
+	return gfMul(a, gfInv(b))
+}
+
+// evaluate evaluates the polynomial with coefficients, lowest degree first, at x.
+func evaluate(coefficients []byte, x byte) byte {
+	// Horner's method.
+	var result byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = gfMul(result, x) ^ coefficients[i]
+	}
+	return result
+}
+
+// interpolate evaluates at x the unique polynomial of degree len(xs)-1 which passes through every point (xs[i], ys[i]).
+// Every xs[i] must be distinct.
+func interpolate(xs, ys []byte, x byte) byte {
+	var result byte
+	for i := range xs {
+		// The Lagrange basis polynomial for xs[i] is 1 at xs[i] and 0 at every other xs[j].
+		basis := byte(1)
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			basis = gfMul(basis, gfDiv(x^xs[j], xs[i]^xs[j]))
+		}
+		result ^= gfMul(ys[i], basis)
+	}
+	return result
+}
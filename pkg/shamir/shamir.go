@@ -0,0 +1,262 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SharePrefix begins every encoded share, so shares are recognizable wherever they are stored.
+	SharePrefix = "jeth-share-"
+	// MaxShares is the most shares a key can be split into, share indexes are a single non-zero byte.
+	MaxShares = 255
+
+	shareVersion = 1
+	// secretLength is the length of the secret shared, a private key.
+	secretLength = 32
+	// shareLength is the length of an encoded share in bytes, version (1) || split id (4) || threshold (1) || total (1) || index (1) || address (20) || value (32) || checksum (4).
+	shareLength = 1 + 4 + 1 + 1 + 1 + common.AddressLength + secretLength + checksumLength
+	// checksumLength is the length of a share's checksum, the first 4 bytes of the double SHA-256 of the rest of the share.
+	checksumLength = 4
+)
+
+// Sentinel errors returned (wrapped) by this package, check for them with errors.Is.
+var (
+	// ErrCorruptShare means a share is malformed, its checksum does not match, or it disagrees with the other shares it was combined with.
+	ErrCorruptShare = errors.New("corrupt share")
+	// ErrDuplicateShare means the same share index was given more than once.
+	ErrDuplicateShare = errors.New("duplicate share")
+	// ErrMismatchedShares means shares from different splits, or of different keys, were combined.
+	ErrMismatchedShares = errors.New("shares are from different splits")
+	// ErrNotEnoughShares means fewer shares than the threshold were given.
+	ErrNotEnoughShares = errors.New("not enough shares")
+	// ErrWrongKey means the shares rebuilt a key which does not belong to the address they were split from.
+	ErrWrongKey = errors.New("shares rebuilt the wrong key")
+)
+
+// Share is one share of a private key split with Split. Any Threshold of the Total shares of a split rebuild the key, fewer reveal nothing about it.
+type Share struct {
+	// SplitID is random and the same for every share of one split, so that shares of different splits are never combined.
+	SplitID [4]byte
+	// Threshold is the number of shares needed to rebuild the key.
+	Threshold int
+	// Total is the number of shares the key was split into.
+	Total int
+	// Index identifies this share among the Total shares of its split, from 1 to Total.
+	Index int
+	// Address is the address of the wallet the key belongs to, it is not secret and lets the rebuilt key be checked.
+	Address string
+	// Value is this share's point on each of the split's polynomials, one per byte of the key.
+	Value []byte
+}
+
+// Split splits w's private key into total shares, any threshold of which rebuild it with Combine.
+// threshold must be at least 2, since with a threshold of 1 every share would simply be the key.
+func Split(w *wallet.Wallet, total, threshold int) ([]Share, error) {
+	if threshold < 2 {
+		return nil, fmt.Errorf("threshold must be at least 2, got %v", threshold)
+	}
+	if total < threshold {
+		return nil, fmt.Errorf("total shares must be at least the threshold, %v, got %v", threshold, total)
+	}
+	if total > MaxShares {
+		return nil, fmt.Errorf("total shares must be at most %v, got %v", MaxShares, total)
+	}
+	if w.IsWatchOnly() {
+		return nil, errors.Wrap(wallet.ErrWatchOnly, "only a wallet with a private key can be split")
+	}
+	if err := w.Validate(); err != nil {
+		return nil, errors.Wrap(err, "validating wallet")
+	}
+
+	secret, err := hex.DecodeString(w.PrivateKeyHex())
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding private key")
+	}
+
+	var splitID [4]byte
+	if _, err := rand.Read(splitID[:]); err != nil {
+		return nil, errors.Wrap(err, "generating split id")
+	}
+
+	shares := make([]Share, total)
+	for i := range shares {
+		shares[i] = Share{
+			SplitID:   splitID,
+			Threshold: threshold,
+			Total:     total,
+			Index:     i + 1,
+			Address:   w.Address(),
+			Value:     make([]byte, len(secret)),
+		}
+	}
+
+	// Each byte of the secret is the constant term of its own random polynomial of degree threshold-1, and each share is a point on every one of them.
+	coefficients := make([]byte, threshold)
+	for b := range secret {
+		coefficients[0] = secret[b]
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, errors.Wrap(err, "generating polynomial coefficients")
+		}
+		for i := range shares {
+			shares[i].Value[b] = evaluate(coefficients, byte(shares[i].Index))
+		}
+	}
+	for i := range coefficients {
+		coefficients[i] = 0
+	}
+
+	return shares, nil
+}
+
+// Combine rebuilds the wallet whose private key was split into shares, which must contain at least the threshold number of shares of one split.
+// The rebuilt wallet is validated and checked to have the address the shares were split from, so a wrong key is never returned.
+// The returned error wraps one of this package's sentinel errors describing what is wrong with shares.
+func Combine(shares []Share) (*wallet.Wallet, error) {
+	if len(shares) == 0 {
+		return nil, errors.Wrap(ErrNotEnoughShares, "no shares given")
+	}
+
+	first := shares[0]
+	seen := map[int]bool{}
+	for _, s := range shares {
+		if err := s.check(); err != nil {
+			return nil, errors.Wrapf(err, "checking share %v", s.Index)
+		}
+		if s.SplitID != first.SplitID || s.Threshold != first.Threshold || s.Total != first.Total || s.Address != first.Address {
+			return nil, errors.Wrapf(ErrMismatchedShares, "share %v of split %x for %s does not belong with share %v of split %x for %s", s.Index, s.SplitID, s.Address, first.Index, first.SplitID, first.Address)
+		}
+		if seen[s.Index] {
+			return nil, errors.Wrapf(ErrDuplicateShare, "share %v was given more than once", s.Index)
+		}
+		seen[s.Index] = true
+	}
+	if len(shares) < first.Threshold {
+		return nil, errors.Wrapf(ErrNotEnoughShares, "%v shares are needed, got %v", first.Threshold, len(shares))
+	}
+
+	// Exactly threshold shares determine the polynomials, any extra shares must lie on them too.
+	used, extra := shares[:first.Threshold], shares[first.Threshold:]
+	xs := make([]byte, len(used))
+	for i, s := range used {
+		xs[i] = byte(s.Index)
+	}
+
+	secret := make([]byte, secretLength)
+	ys := make([]byte, len(used))
+	for b := range secret {
+		for i, s := range used {
+			ys[i] = s.Value[b]
+		}
+		secret[b] = interpolate(xs, ys, 0)
+		for _, s := range extra {
+			if interpolate(xs, ys, byte(s.Index)) != s.Value[b] {
+				return nil, errors.Wrapf(ErrCorruptShare, "share %v is inconsistent with the other shares", s.Index)
+			}
+		}
+	}
+
+	w, err := wallet.FromPrivateKeyHex(hex.EncodeToString(secret))
+	for i := range secret {
+		secret[i] = 0
+	}
+	if err != nil {
+		return nil, errors.Wrap(ErrWrongKey, err.Error())
+	}
+	if err := w.Validate(); err != nil {
+		return nil, errors.Wrap(ErrWrongKey, err.Error())
+	}
+	if w.Address() != first.Address {
+		return nil, errors.Wrapf(ErrWrongKey, "rebuilt key belongs to %s, but the shares were split from %s", w.Address(), first.Address)
+	}
+
+	return w, nil
+}
+
+// String encodes s as SharePrefix followed by hexadecimal, the format ParseShare accepts.
+func (s Share) String() string {
+	return SharePrefix + hex.EncodeToString(s.bytes())
+}
+
+// ParseShare decodes a share encoded by Share.String. Surrounding whitespace is ignored.
+// The returned error wraps ErrCorruptShare if the share is malformed or its checksum does not match.
+func ParseShare(encoded string) (Share, error) {
+	encoded = strings.TrimSpace(encoded)
+	if !strings.HasPrefix(encoded, SharePrefix) {
+		return Share{}, errors.Wrapf(ErrCorruptShare, "share must begin with \"%s\"", SharePrefix)
+	}
+
+	bs, err := hex.DecodeString(strings.TrimPrefix(encoded, SharePrefix))
+	if err != nil {
+		return Share{}, errors.Wrap(ErrCorruptShare, err.Error())
+	}
+	if len(bs) != shareLength {
+		return Share{}, errors.Wrapf(ErrCorruptShare, "share must be %v bytes, got %v", shareLength, len(bs))
+	}
+
+	body, checksum := bs[:shareLength-checksumLength], bs[shareLength-checksumLength:]
+	if !bytes.Equal(checksum, shareChecksum(body)) {
+		return Share{}, errors.Wrap(ErrCorruptShare, "checksum does not match, the share has been altered or mistyped")
+	}
+	if body[0] != shareVersion {
+		return Share{}, errors.Wrapf(ErrCorruptShare, "unsupported share version %v", body[0])
+	}
+
+	s := Share{
+		Threshold: int(body[5]),
+		Total:     int(body[6]),
+		Index:     int(body[7]),
+		Address:   common.BytesToAddress(body[8 : 8+common.AddressLength]).Hex(),
+		Value:     append([]byte(nil), body[8+common.AddressLength:]...),
+	}
+	copy(s.SplitID[:], body[1:5])
+	if err := s.check(); err != nil {
+		return Share{}, err
+	}
+
+	return s, nil
+}
+
+// check checks that s's fields are consistent with each other, the returned error wraps ErrCorruptShare.
+func (s Share) check() error {
+	if s.Threshold < 2 || s.Total < s.Threshold || s.Total > MaxShares {
+		return errors.Wrapf(ErrCorruptShare, "threshold of %v is invalid for %v total shares", s.Threshold, s.Total)
+	}
+	if s.Index < 1 || s.Index > s.Total {
+		return errors.Wrapf(ErrCorruptShare, "index %v is not between 1 and %v", s.Index, s.Total)
+	}
+	if len(s.Value) != secretLength {
+		return errors.Wrapf(ErrCorruptShare, "value must be %v bytes, got %v", secretLength, len(s.Value))
+	}
+	if !common.IsHexAddress(s.Address) {
+		return errors.Wrapf(ErrCorruptShare, "\"%s\" is not a valid address", s.Address)
+	}
+	return nil
+}
+
+// bytes encodes s, including its checksum.
+func (s Share) bytes() []byte {
+	body := make([]byte, 0, shareLength)
+	body = append(body, shareVersion)
+	body = append(body, s.SplitID[:]...)
+	body = append(body, byte(s.Threshold), byte(s.Total), byte(s.Index))
+	body = append(body, common.HexToAddress(s.Address).Bytes()...)
+	body = append(body, s.Value...)
+	return append(body, shareChecksum(body)...)
+}
+
+// shareChecksum returns the first 4 bytes of the double SHA-256 of body.
+func shareChecksum(body []byte) []byte {
+	first := sha256.Sum256(body)
+	second := sha256.Sum256(first[:])
+	return second[:checksumLength]
+}
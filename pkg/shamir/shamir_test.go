@@ -0,0 +1,132 @@
+package shamir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_gfMul(t *testing.T) {
+	// This pair is the worked example of multiplicative inverses in the AES specification.
+	assert.Equal(t, byte(0x01), gfMul(0x53, 0xca))
+	assert.Equal(t, byte(0xca), gfInv(0x53))
+	assert.Equal(t, byte(0xc1), gfMul(0x57, 0x83))
+
+	for a := 1; a < 256; a++ {
+		assert.Equal(t, byte(1), gfMul(byte(a), gfInv(byte(a))), "inverse of %v", a)
+		assert.Equal(t, byte(0), gfMul(byte(a), 0))
+	}
+}
+
+func Test_interpolate(t *testing.T) {
+	coefficients := []byte{42, 7, 199}
+	xs := []byte{3, 9, 200}
+	ys := []byte{evaluate(coefficients, 3), evaluate(coefficients, 9), evaluate(coefficients, 200)}
+
+	assert.Equal(t, byte(42), interpolate(xs, ys, 0))
+	assert.Equal(t, evaluate(coefficients, 17), interpolate(xs, ys, 17))
+}
+
+func Test_Split_Combine(t *testing.T) {
+	w := wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+
+	shares, err := Split(w, 5, 3)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+
+	// Every combination of 3 or more shares rebuilds the wallet.
+	for a := 0; a < 5; a++ {
+		for b := a + 1; b < 5; b++ {
+			for c := b + 1; c < 5; c++ {
+				w2, err := Combine([]Share{shares[c], shares[a], shares[b]})
+				require.NoError(t, err)
+				assert.NoError(t, w.Equals(w2))
+			}
+		}
+	}
+	w2, err := Combine(shares)
+	require.NoError(t, err)
+	assert.NoError(t, w.Equals(w2))
+}
+
+func Test_Split_Invalid(t *testing.T) {
+	w := wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+
+	_, err := Split(w, 5, 1)
+	assert.Error(t, err)
+	_, err = Split(w, 2, 3)
+	assert.Error(t, err)
+	_, err = Split(w, 256, 3)
+	assert.Error(t, err)
+}
+
+func Test_ParseShare(t *testing.T) {
+	w := wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+	shares, err := Split(w, 3, 2)
+	require.NoError(t, err)
+
+	encoded := shares[1].String()
+	assert.True(t, strings.HasPrefix(encoded, SharePrefix))
+	assert.NotContains(t, encoded, wallettest.PrivateKeyHex)
+
+	s, err := ParseShare("  " + encoded + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, shares[1], s)
+	assert.Equal(t, wallettest.Address, s.Address)
+
+	// Flip one hexadecimal character anywhere in the share.
+	for i := len(SharePrefix); i < len(encoded); i++ {
+		flipped := []byte(encoded)
+		if flipped[i] == '0' {
+			flipped[i] = '1'
+		} else {
+			flipped[i] = '0'
+		}
+		_, err := ParseShare(string(flipped))
+		assert.True(t, errors.Is(err, ErrCorruptShare), "flipping character %v: expected %v, got %v", i, ErrCorruptShare, err)
+	}
+
+	_, err = ParseShare(encoded[:len(encoded)-2])
+	assert.True(t, errors.Is(err, ErrCorruptShare))
+	_, err = ParseShare(strings.TrimPrefix(encoded, SharePrefix))
+	assert.True(t, errors.Is(err, ErrCorruptShare))
+}
+
+func Test_Combine_Errors(t *testing.T) {
+	w := wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+	shares, err := Split(w, 5, 3)
+	require.NoError(t, err)
+	otherShares, err := Split(w, 5, 3)
+	require.NoError(t, err)
+
+	corrupt := shares[3]
+	corrupt.Value = append([]byte(nil), corrupt.Value...)
+	corrupt.Value[0] ^= 0xff
+
+	tests := []struct {
+		name   string
+		shares []Share
+		err    error
+	}{
+		{name: "none", shares: nil, err: ErrNotEnoughShares},
+		{name: "below threshold", shares: shares[:2], err: ErrNotEnoughShares},
+		{name: "duplicate", shares: []Share{shares[0], shares[1], shares[0]}, err: ErrDuplicateShare},
+		{name: "different splits", shares: []Share{shares[0], shares[1], otherShares[2]}, err: ErrMismatchedShares},
+		{name: "corrupt extra share", shares: []Share{shares[0], shares[1], shares[2], corrupt}, err: ErrCorruptShare},
+		{name: "corrupt share", shares: []Share{shares[0], shares[1], corrupt}, err: ErrWrongKey},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Combine(test.shares)
+
+			assert.True(t, errors.Is(err, test.err), "expected %v, got %v", test.err, err)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package signer
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// Keystore is a Signer backed by an encrypted keystore file.
+// The keystore is only decrypted for the duration of each signature, so unlike Wallet the private key is not held in memory between signatures.
+type Keystore struct {
+	keystoreBytes []byte
+	passphrase    string
+	address       string
+	destroyed     bool
+}
+
+var (
+	_ Signer    = (*Keystore)(nil)
+	_ Destroyer = (*Keystore)(nil)
+)
+
+// NewKeystore creates a new Keystore signer from the keystore file at path, encrypted with passphrase.
+// The keystore is decrypted once up front, so a wrong passphrase or a corrupt file is reported here rather than at the first signature.
+func NewKeystore(path, passphrase string) (*Keystore, error) {
+	keystoreBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading keystore file")
+	}
+
+	w, err := wallet.FromKeystore(keystoreBytes, passphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting keystore")
+	}
+	defer w.Destroy()
+
+	s := &Keystore{
+		keystoreBytes: keystoreBytes,
+		passphrase:    passphrase,
+		address:       w.Address(),
+	}
+
+	return s, nil
+}
+
+// Address returns the address of s's keystore.
+func (s *Keystore) Address() string {
+	return s.address
+}
+
+// SignTx decrypts s's keystore and signs tx with it.
+func (s *Keystore) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	w, err := s.unlock()
+	if err != nil {
+		return nil, errors.Wrap(err, "unlocking keystore")
+	}
+	defer w.Destroy()
+
+	return w.SignTx(tx, chainID)
+}
+
+// SignHash decrypts s's keystore and signs hash with it.
+func (s *Keystore) SignHash(_ context.Context, hash []byte) ([]byte, error) {
+	w, err := s.unlock()
+	if err != nil {
+		return nil, errors.Wrap(err, "unlocking keystore")
+	}
+	defer w.Destroy()
+
+	return w.SignHash(hash)
+}
+
+// Destroy forgets s's passphrase, so its keystore can no longer be decrypted.
+// The keystore itself stays encrypted in memory, only the passphrase needs forgetting.
+func (s *Keystore) Destroy() {
+	s.passphrase = ""
+	s.destroyed = true
+}
+
+// unlock decrypts s's keystore into a wallet.Wallet.
+func (s *Keystore) unlock() (*wallet.Wallet, error) {
+	if s.destroyed {
+		return nil, errors.New("keystore signer has been destroyed")
+	}
+	return wallet.FromKeystore(s.keystoreBytes, s.passphrase)
+}
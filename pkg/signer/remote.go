@@ -0,0 +1,161 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultRemoteURL is the address Clef serves its HTTP JSON-RPC API on by default.
+	DefaultRemoteURL = "http://localhost:8550"
+)
+
+// remoteTxArgs are the arguments of Clef's account_signTransaction.
+type remoteTxArgs struct {
+	From                 common.MixedcaseAddress  `json:"from"`
+	To                   *common.MixedcaseAddress `json:"to"`
+	Gas                  hexutil.Uint64           `json:"gas"`
+	GasPrice             *hexutil.Big             `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big             `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big             `json:"maxPriorityFeePerGas,omitempty"`
+	Value                hexutil.Big              `json:"value"`
+	Nonce                hexutil.Uint64           `json:"nonce"`
+	Data                 *hexutil.Bytes           `json:"data"`
+	AccessList           *types.AccessList        `json:"accessList,omitempty"`
+	ChainID              *hexutil.Big             `json:"chainId,omitempty"`
+}
+
+// remoteSignTxResult is the result of Clef's account_signTransaction.
+type remoteSignTxResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+// Remote is a Signer backed by a remote signer speaking Clef's HTTP JSON-RPC API, the private key never leaves the remote signer.
+// Every signature is subject to whatever approval the remote signer requires, which may well be a human confirming it.
+type Remote struct {
+	client  *rpc.Client
+	address string
+}
+
+var (
+	_ Signer = (*Remote)(nil)
+	_ Closer = (*Remote)(nil)
+)
+
+// DialRemote connects to the Clef-compatible signer at url and creates a new Remote signer which signs for address.
+// If address is blank the signer is asked for its accounts, and if it has exactly one that is used.
+func DialRemote(ctx context.Context, url, address string) (*Remote, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "dialing remote signer")
+	}
+
+	if address == "" {
+		var accounts []common.Address
+		if err := client.CallContext(ctx, &accounts, "account_list"); err != nil {
+			client.Close()
+			return nil, errors.Wrap(err, "listing remote signer's accounts")
+		}
+		if len(accounts) != 1 {
+			client.Close()
+			return nil, fmt.Errorf("remote signer has %v accounts, an address must be given to choose one", len(accounts))
+		}
+		address = accounts[0].Hex()
+	}
+
+	if !common.IsHexAddress(address) {
+		client.Close()
+		return nil, fmt.Errorf("\"%s\" is not a valid address", address)
+	}
+
+	s := &Remote{
+		client:  client,
+		address: common.HexToAddress(address).Hex(),
+	}
+
+	return s, nil
+}
+
+// Address returns the address s signs for.
+func (s *Remote) Address() string {
+	return s.address
+}
+
+// SignTx asks s's remote signer to sign tx.
+// The signed transaction is checked to be tx, unaltered, signed by s's address for chainID before it is returned, so a misbehaving signer cannot substitute a different transaction.
+func (s *Remote) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if chainID == nil || chainID.Sign() <= 0 {
+		return nil, errors.New("chain id must be positive")
+	}
+
+	args := remoteTxArgs{
+		From:    common.NewMixedcaseAddress(common.HexToAddress(s.address)),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+	data := hexutil.Bytes(tx.Data())
+	args.Data = &data
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.AccessListTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	default:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	}
+
+	var result remoteSignTxResult
+	// args is passed by pointer since common.MixedcaseAddress only marshals to a plain address through a pointer receiver.
+	if err := s.client.CallContext(ctx, &result, "account_signTransaction", &args); err != nil {
+		return nil, errors.Wrap(err, "calling remote signer's account_signTransaction")
+	}
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(result.Raw); err != nil {
+		return nil, errors.Wrap(err, "decoding signed transaction")
+	}
+
+	// The signing hash covers every field except the signature, so if it matches nothing was altered.
+	txSigner := types.LatestSignerForChainID(chainID)
+	if txSigner.Hash(signedTx) != txSigner.Hash(tx) {
+		return nil, errors.New("remote signer returned a different transaction than the one it was asked to sign")
+	}
+	sender, err := types.Sender(txSigner, signedTx)
+	if err != nil {
+		return nil, errors.Wrap(err, "recovering signed transaction's sender")
+	}
+	if sender.Hex() != s.address {
+		return nil, fmt.Errorf("remote signer signed as \"%s\", but was asked to sign as \"%s\"", sender.Hex(), s.address)
+	}
+
+	return signedTx, nil
+}
+
+// SignHash always fails, Clef deliberately refuses to sign raw hashes since it cannot show its user what they would be approving.
+func (s *Remote) SignHash(_ context.Context, _ []byte) ([]byte, error) {
+	return nil, errors.Wrap(ErrUnsupported, "remote signers only sign content they can display, never raw hashes")
+}
+
+// Close closes s's connection to its remote signer.
+func (s *Remote) Close() {
+	s.client.Close()
+}
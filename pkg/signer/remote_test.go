@@ -0,0 +1,122 @@
+package signer
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clefStandIn serves the parts of Clef's "account" API that Remote uses, signing with an in-memory wallet and approving everything.
+type clefStandIn struct {
+	w *wallet.Wallet
+	// tamper, if set, is applied to every transaction before it is signed, imitating a misbehaving signer.
+	tamper func(tx *types.LegacyTx)
+}
+
+func (c *clefStandIn) List() []common.Address {
+	return []common.Address{common.HexToAddress(c.w.Address())}
+}
+
+func (c *clefStandIn) SignTransaction(args remoteTxArgs, _ *string) (*remoteSignTxResult, error) {
+	var to *common.Address
+	if args.To != nil {
+		address := args.To.Address()
+		to = &address
+	}
+	var data []byte
+	if args.Data != nil {
+		data = *args.Data
+	}
+	chainID := args.ChainID.ToInt()
+
+	var tx *types.Transaction
+	switch {
+	case args.MaxFeePerGas != nil:
+		tx = types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: uint64(args.Nonce), To: to, Value: args.Value.ToInt(), Gas: uint64(args.Gas), GasTipCap: args.MaxPriorityFeePerGas.ToInt(), GasFeeCap: args.MaxFeePerGas.ToInt(), Data: data, AccessList: *args.AccessList})
+	case args.AccessList != nil:
+		tx = types.NewTx(&types.AccessListTx{ChainID: chainID, Nonce: uint64(args.Nonce), To: to, Value: args.Value.ToInt(), Gas: uint64(args.Gas), GasPrice: args.GasPrice.ToInt(), Data: data, AccessList: *args.AccessList})
+	default:
+		legacyTx := &types.LegacyTx{Nonce: uint64(args.Nonce), To: to, Value: args.Value.ToInt(), Gas: uint64(args.Gas), GasPrice: args.GasPrice.ToInt(), Data: data}
+		if c.tamper != nil {
+			c.tamper(legacyTx)
+		}
+		tx = types.NewTx(legacyTx)
+	}
+
+	signedTx, err := c.w.SignTx(tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &remoteSignTxResult{Raw: raw, Tx: signedTx}, nil
+}
+
+// newClefStandIn serves c over HTTP JSON-RPC, the returned function shuts the server down.
+func newClefStandIn(t *testing.T, c *clefStandIn) (string, func()) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("account", c))
+	httpServer := httptest.NewServer(server)
+
+	return httpServer.URL, func() {
+		httpServer.Close()
+		server.Stop()
+	}
+}
+
+func Test_Remote(t *testing.T) {
+	url, stop := newClefStandIn(t, &clefStandIn{w: wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex)})
+	defer stop()
+
+	// The only account is chosen when no address is given.
+	s, err := DialRemote(context.Background(), url, "")
+	require.NoError(t, err)
+	defer s.Close()
+
+	testSigner(t, s)
+
+	_, err = s.SignHash(context.Background(), wallet.MessageHash([]byte("jeth")))
+	assert.True(t, errors.Is(err, ErrUnsupported), "expected %v, got %v", ErrUnsupported, err)
+}
+
+func Test_Remote_Tampered(t *testing.T) {
+	url, stop := newClefStandIn(t, &clefStandIn{
+		w:      wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex),
+		tamper: func(tx *types.LegacyTx) { tx.Value = big.NewInt(2e18) },
+	})
+	defer stop()
+
+	s, err := DialRemote(context.Background(), url, wallettest.Address)
+	require.NoError(t, err)
+	defer s.Close()
+
+	_, err = s.SignTx(context.Background(), newTestTxs()["legacy"], chainID)
+	assert.Error(t, err)
+}
+
+func Test_Remote_WrongAccount(t *testing.T) {
+	url, stop := newClefStandIn(t, &clefStandIn{w: wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex)})
+	defer stop()
+
+	// The stand-in signs with its own key no matter which address it is asked to sign as.
+	s, err := DialRemote(context.Background(), url, "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB")
+	require.NoError(t, err)
+	defer s.Close()
+
+	_, err = s.SignTx(context.Background(), newTestTxs()["legacy"], chainID)
+	assert.Error(t, err)
+}
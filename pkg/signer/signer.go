@@ -0,0 +1,75 @@
+package signer
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUnsupported means a signer refuses, by design, to perform the requested kind of signature.
+	ErrUnsupported = errors.New("unsupported by signer")
+)
+
+// Signer signs on behalf of a single address, wherever its private key actually lives.
+// Code that only needs signatures should depend on a Signer rather than on a private key, so the key can be kept in memory, encrypted on disk, or on another machine entirely.
+type Signer interface {
+	// Address returns the EIP-55 checksummed address this signer signs for.
+	Address() string
+	// SignTx signs tx for the chain identified by chainID, returning the signed copy of tx.
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// SignHash signs a 32 byte hash, returning the signature in its 65 byte r || s || v form, where v is the raw recovery id, 0 or 1.
+	// Signers which will not sign a hash they cannot see the contents of return an error wrapping ErrUnsupported.
+	SignHash(ctx context.Context, hash []byte) ([]byte, error)
+}
+
+// Destroyer is implemented by signers which hold key material in memory. Destroy wipes it, after which the signer can no longer sign.
+// Callers should check for it and call Destroy as soon as they have nothing left to sign.
+type Destroyer interface {
+	Destroy()
+}
+
+// Closer is implemented by signers which hold a connection open, such as Remote. Close closes it, after which the signer can no longer sign.
+// Callers should check for it and call Close once they have nothing left to sign.
+type Closer interface {
+	Close()
+}
+
+// Wallet is a Signer backed by an in-memory wallet.Wallet, its private key is held in memory for as long as the Wallet is.
+type Wallet struct {
+	w *wallet.Wallet
+}
+
+var (
+	_ Signer    = (*Wallet)(nil)
+	_ Destroyer = (*Wallet)(nil)
+)
+
+// NewWallet creates a new Wallet signer which signs with w.
+func NewWallet(w *wallet.Wallet) *Wallet {
+	return &Wallet{w: w}
+}
+
+// Address returns the address of s's wallet.
+func (s *Wallet) Address() string {
+	return s.w.Address()
+}
+
+// SignTx signs tx with s's wallet.
+func (s *Wallet) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.w.SignTx(tx, chainID)
+}
+
+// SignHash signs hash with s's wallet.
+func (s *Wallet) SignHash(_ context.Context, hash []byte) ([]byte, error) {
+	return s.w.SignHash(hash)
+}
+
+// Destroy wipes the private key of s's wallet, see wallet.Wallet.Destroy.
+func (s *Wallet) Destroy() {
+	s.w.Destroy()
+}
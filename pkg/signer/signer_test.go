@@ -0,0 +1,92 @@
+package signer
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	chainID = big.NewInt(1)
+)
+
+// newTestTxs returns one unsigned transaction of every type.
+func newTestTxs() map[string]*types.Transaction {
+	to := common.HexToAddress("0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB")
+	return map[string]*types.Transaction{
+		"legacy":      types.NewTx(&types.LegacyTx{Nonce: 7, To: &to, Value: big.NewInt(1e18), Gas: 21000, GasPrice: big.NewInt(1e9)}),
+		"access list": types.NewTx(&types.AccessListTx{ChainID: chainID, Nonce: 7, To: &to, Value: big.NewInt(1e18), Gas: 21000, GasPrice: big.NewInt(1e9)}),
+		"dynamic fee": types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 7, To: &to, Value: big.NewInt(1e18), Gas: 21000, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(2e9), Data: []byte{0xca, 0xfe}}),
+	}
+}
+
+// testSigner checks that s signs transactions of every type as s.Address().
+func testSigner(t *testing.T, s Signer) {
+	assert.Equal(t, wallettest.Address, s.Address())
+
+	for name, tx := range newTestTxs() {
+		t.Run(name, func(t *testing.T) {
+			signedTx, err := s.SignTx(context.Background(), tx, chainID)
+			require.NoError(t, err)
+
+			sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+			require.NoError(t, err)
+			assert.Equal(t, wallettest.Address, sender.Hex())
+			assert.Equal(t, tx.Type(), signedTx.Type())
+		})
+	}
+}
+
+func Test_Wallet(t *testing.T) {
+	s := NewWallet(wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex))
+
+	testSigner(t, s)
+
+	hash := wallet.MessageHash([]byte("jeth"))
+	signature, err := s.SignHash(context.Background(), hash)
+	require.NoError(t, err)
+	assert.NoError(t, wallet.VerifyHash(wallettest.Address, hash, signature))
+
+	s.Destroy()
+	_, err = s.SignHash(context.Background(), hash)
+	assert.Error(t, err)
+	assert.Equal(t, wallettest.Address, s.Address())
+}
+
+func Test_Keystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jeth-signer")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	w := wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+	path, err := w.WriteKeystoreFile(dir, "passphrase", wallet.KeystoreOptions{KDF: wallet.KDFScrypt, ScryptN: 1 << 4, ScryptP: 1})
+	require.NoError(t, err)
+
+	_, err = NewKeystore(path, "not the passphrase")
+	assert.Error(t, err)
+
+	s, err := NewKeystore(path, "passphrase")
+	require.NoError(t, err)
+
+	testSigner(t, s)
+
+	hash := wallet.MessageHash([]byte("jeth"))
+	signature, err := s.SignHash(context.Background(), hash)
+	require.NoError(t, err)
+	assert.NoError(t, wallet.VerifyHash(wallettest.Address, hash, signature))
+
+	s.Destroy()
+	_, err = s.SignHash(context.Background(), hash)
+	assert.Error(t, err)
+	assert.Equal(t, wallettest.Address, s.Address())
+}
@@ -0,0 +1,385 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// HomeEnv is the environment variable which overrides DefaultDir.
+	HomeEnv = "JETH_HOME"
+	// RefPrefix marks a reference to a stored entry by name, such as "@alice", wherever an address is expected.
+	RefPrefix = "@"
+
+	storeFileName   = "store.json"
+	keystoreDirName = "keystores"
+	storeVersion    = 1
+)
+
+// Kind is what an Entry describes.
+type Kind string
+
+const (
+	// KindWallet is one of our own wallets, either watch-only or backed by a keystore file held in the store.
+	KindWallet Kind = "wallet"
+	// KindContact is someone else's address.
+	KindContact Kind = "contact"
+)
+
+var (
+	// ErrNotFound means no entry has the given name.
+	ErrNotFound = errors.New("no such entry")
+	// ErrExists means an entry already has the given name.
+	ErrExists = errors.New("entry already exists")
+	// ErrInvalidName means a name is not allowed, see Entry.Name.
+	ErrInvalidName = errors.New("invalid name")
+
+	nameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+)
+
+// Entry is a named address in a Store.
+type Entry struct {
+	// Name identifies the entry, it is referred to as "@name". Names start with a letter or digit, contain only letters, digits, ".", "_", and "-", and are unique regardless of case.
+	Name string `json:"name"`
+	Kind Kind   `json:"kind"`
+	// Address is the entry's address.
+	Address wallet.Address `json:"address"`
+	// Keystore is the file name of a keystore-backed wallet's keystore file within the store's keystore directory, see Store.KeystorePath. It is blank for watch-only wallets and contacts.
+	Keystore string `json:"keystore,omitempty"`
+	// Label is a short human readable description shown next to the address, such as "Alice (payroll)".
+	Label string `json:"label,omitempty"`
+	// Notes is free text.
+	Notes string `json:"notes,omitempty"`
+}
+
+// IsKeystoreBacked reports whether e is a wallet whose keystore file is held in the store.
+func (e Entry) IsKeystoreBacked() bool {
+	return e.Keystore != ""
+}
+
+// Display returns e's address alongside its label, or its name if it has no label, for showing to a person.
+func (e Entry) Display() string {
+	label := e.Label
+	if label == "" {
+		label = RefPrefix + e.Name
+	}
+	return fmt.Sprintf("%s (%s)", e.Address, label)
+}
+
+// Store is a directory holding named wallets and contacts.
+// The entries live in a single JSON file, keystore files of keystore-backed wallets are copied in alongside it, and nothing is written until Save is called.
+// A Store is not safe for concurrent use, nor for use by several processes at once.
+type Store struct {
+	dir     string
+	entries []Entry
+}
+
+// storeJSON is the on-disk format of a Store.
+type storeJSON struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+// DefaultDir returns the directory of the default Store, $JETH_HOME if it is set and ~/.jeth otherwise.
+func DefaultDir() (string, error) {
+	if dir := os.Getenv(HomeEnv); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrapf(err, "finding home directory, set %s instead", HomeEnv)
+	}
+
+	return filepath.Join(home, ".jeth"), nil
+}
+
+// Open opens the Store in dir. A directory which does not exist yet is an empty Store, it is created on Save.
+func Open(dir string) (*Store, error) {
+	s := &Store{dir: dir}
+
+	b, err := ioutil.ReadFile(s.path())
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading store file")
+	}
+
+	var sj storeJSON
+	if err := json.Unmarshal(b, &sj); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling store file \"%s\"", s.path())
+	}
+	if sj.Version != storeVersion {
+		return nil, fmt.Errorf("unsupported store version %v, only version %v is supported", sj.Version, storeVersion)
+	}
+	s.entries = sj.Entries
+
+	return s, nil
+}
+
+// OpenDefault opens the Store in DefaultDir.
+func OpenDefault() (*Store, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting default store directory")
+	}
+
+	return Open(dir)
+}
+
+// Dir returns the directory of s.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save writes s to disk. The store file is replaced atomically and, like the directory, is only accessible by its owner.
+func (s *Store) Save() error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return errors.Wrapf(err, "creating store directory \"%s\"", s.dir)
+	}
+
+	b, err := json.MarshalIndent(storeJSON{Version: storeVersion, Entries: s.entries}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshalling store")
+	}
+
+	tmp := s.path() + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return errors.Wrap(err, "writing store file")
+	}
+	if err := os.Rename(tmp, s.path()); err != nil {
+		return errors.Wrap(err, "replacing store file")
+	}
+
+	return nil
+}
+
+// List returns every entry in s, wallets first, each sorted by name.
+func (s *Store) List() []Entry {
+	entries := make([]Entry, len(s.entries))
+	copy(entries, s.entries)
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind == KindWallet
+		}
+		return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+	})
+	return entries
+}
+
+// Get returns the entry named name, with or without its "@" prefix. The returned error wraps ErrNotFound if there is none.
+func (s *Store) Get(name string) (Entry, error) {
+	i := s.index(name)
+	if i < 0 {
+		return Entry{}, errors.Wrapf(ErrNotFound, "\"%s\"", name)
+	}
+	return s.entries[i], nil
+}
+
+// LookupAddress returns the first entry, in List order, whose address is address.
+func (s *Store) LookupAddress(address wallet.Address) (Entry, bool) {
+	for _, e := range s.List() {
+		if e.Address == address {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Add adds e to s. e.Keystore must be blank, keystore-backed wallets are added with AddKeystore.
+func (s *Store) Add(e Entry) error {
+	if e.Keystore != "" {
+		return errors.New("keystore-backed wallets must be added with AddKeystore")
+	}
+
+	return s.add(e)
+}
+
+// AddKeystore adds the keystore-backed wallet e, copying the keystore file at keystorePath into s's keystore directory.
+// e.Address is taken from the keystore file. The keystore is not decrypted, it is checked against its address whenever it is.
+// Unlike Add, the keystore file is written immediately, Save must still be called to keep the entry.
+func (s *Store) AddKeystore(e Entry, keystorePath string) error {
+	if e.Kind != KindWallet {
+		return fmt.Errorf("only a \"%s\" can be keystore-backed, got \"%s\"", KindWallet, e.Kind)
+	}
+
+	keystoreBytes, err := ioutil.ReadFile(keystorePath)
+	if err != nil {
+		return errors.Wrap(err, "reading keystore file")
+	}
+	e.Address, err = wallet.KeystoreAddress(keystoreBytes)
+	if err != nil {
+		return errors.Wrap(err, "reading address from keystore")
+	}
+	e.Keystore = filepath.Base(keystorePath)
+
+	// Validate before touching the disk, so a bad entry never leaves a stray keystore file behind.
+	if err := s.validate(e); err != nil {
+		return err
+	}
+	for _, other := range s.entries {
+		if other.Keystore == e.Keystore {
+			return errors.Wrapf(ErrExists, "keystore file \"%s\" already belongs to \"%s\"", e.Keystore, other.Name)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Join(s.dir, keystoreDirName), 0700); err != nil {
+		return errors.Wrap(err, "creating keystore directory")
+	}
+	// A removed wallet's keystore file stays behind, see Remove, and may be the only copy of its key, so it is never overwritten.
+	f, err := os.OpenFile(s.KeystorePath(e), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return errors.Wrapf(ErrExists, "keystore file \"%s\" is already in the store's keystore directory, most likely left by a removed wallet, rename the file being added", e.Keystore)
+	}
+	if err != nil {
+		return errors.Wrap(err, "creating keystore file copy")
+	}
+	_, err = f.Write(keystoreBytes)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrap(err, "copying keystore file")
+	}
+
+	return s.add(e)
+}
+
+// Rename renames the entry named from to to.
+func (s *Store) Rename(from, to string) error {
+	i := s.index(from)
+	if i < 0 {
+		return errors.Wrapf(ErrNotFound, "\"%s\"", from)
+	}
+
+	to = strings.TrimPrefix(to, RefPrefix)
+	if err := validateName(to); err != nil {
+		return err
+	}
+	// Renaming to a different casing of the same name is allowed.
+	if j := s.index(to); j >= 0 && j != i {
+		return errors.Wrapf(ErrExists, "\"%s\"", to)
+	}
+
+	s.entries[i].Name = to
+	return nil
+}
+
+// Remove removes the entry named name and returns it.
+// The keystore file of a keystore-backed wallet is left in s's keystore directory, see KeystorePath, it may be the only copy of the key.
+func (s *Store) Remove(name string) (Entry, error) {
+	i := s.index(name)
+	if i < 0 {
+		return Entry{}, errors.Wrapf(ErrNotFound, "\"%s\"", name)
+	}
+
+	e := s.entries[i]
+	s.entries = append(s.entries[:i], s.entries[i+1:]...)
+	return e, nil
+}
+
+// KeystorePath returns the path of e's keystore file within s, or "" if e is not keystore-backed.
+func (s *Store) KeystorePath(e Entry) string {
+	if !e.IsKeystoreBacked() {
+		return ""
+	}
+	return filepath.Join(s.dir, keystoreDirName, e.Keystore)
+}
+
+// Resolve resolves ref, either an address or "@name", to an address.
+// The entry the address belongs to is also returned, if there is one, so it can be shown alongside the address.
+func (s *Store) Resolve(ref string) (wallet.Address, *Entry, error) {
+	if strings.HasPrefix(ref, RefPrefix) {
+		e, err := s.Get(ref)
+		if err != nil {
+			return wallet.Address{}, nil, err
+		}
+		return e.Address, &e, nil
+	}
+
+	address, err := wallet.ParseAddress(ref)
+	if err != nil {
+		return wallet.Address{}, nil, errors.Wrapf(err, "\"%s\" is neither an address nor a \"%sname\"", ref, RefPrefix)
+	}
+	if e, ok := s.LookupAddress(address); ok {
+		return address, &e, nil
+	}
+	return address, nil, nil
+}
+
+// Display returns address alongside the label of its entry if s has one, for showing to a person.
+func (s *Store) Display(address wallet.Address) string {
+	if e, ok := s.LookupAddress(address); ok {
+		return e.Display()
+	}
+	return address.Hex()
+}
+
+func (s *Store) add(e Entry) error {
+	if err := s.validate(e); err != nil {
+		return err
+	}
+
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+// CheckName checks that an entry may be added to s under name, that is name is allowed and no entry has it yet.
+// It lets commands reject a name before doing work, such as generating a wallet, that must not be wasted.
+func (s *Store) CheckName(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	if s.index(name) >= 0 {
+		return errors.Wrapf(ErrExists, "\"%s\"", name)
+	}
+	return nil
+}
+
+// validate checks that e may be added to s.
+func (s *Store) validate(e Entry) error {
+	if err := s.CheckName(e.Name); err != nil {
+		return err
+	}
+	if e.Kind != KindWallet && e.Kind != KindContact {
+		return fmt.Errorf("unknown kind \"%s\", must be \"%s\" or \"%s\"", e.Kind, KindWallet, KindContact)
+	}
+	if e.Address.IsZero() {
+		return errors.New("address must not be the zero address")
+	}
+	return nil
+}
+
+// index returns the index of the entry named name, with or without its "@" prefix, or -1 if there is none.
+func (s *Store) index(name string) int {
+	name = strings.TrimPrefix(name, RefPrefix)
+	for i, e := range s.entries {
+		if strings.EqualFold(e.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Store) path() string {
+	return filepath.Join(s.dir, storeFileName)
+}
+
+// validateName checks that name is allowed, see Entry.Name.
+func validateName(name string) error {
+	if !nameRegex.MatchString(name) {
+		return errors.Wrapf(ErrInvalidName, "\"%s\" must start with a letter or digit and contain only letters, digits, \".\", \"_\", and \"-\"", name)
+	}
+	return nil
+}
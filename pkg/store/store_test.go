@@ -0,0 +1,304 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	contactAddress = "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"
+)
+
+// newTestStore returns an empty Store in a temporary directory and a function to remove it.
+func newTestStore(t *testing.T) (*Store, func()) {
+	dir, err := ioutil.TempDir("", "jeth-store")
+	require.NoError(t, err)
+
+	s, err := Open(filepath.Join(dir, "store"))
+	require.NoError(t, err)
+
+	return s, func() { _ = os.RemoveAll(dir) }
+}
+
+func Test_Store_Save(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+
+	require.NoError(t, s.Add(Entry{Name: "alice", Kind: KindContact, Address: wallet.MustParseAddress(contactAddress), Label: "Alice", Notes: "payroll"}))
+	require.NoError(t, s.Add(Entry{Name: "cold", Kind: KindWallet, Address: wallet.MustParseAddress(wallettest.Address)}))
+	require.NoError(t, s.Save())
+
+	info, err := os.Stat(filepath.Join(s.Dir(), storeFileName))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	s2, err := Open(s.Dir())
+	require.NoError(t, err)
+	assert.Equal(t, s.List(), s2.List())
+}
+
+func Test_Store_List(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+
+	require.NoError(t, s.Add(Entry{Name: "bob", Kind: KindContact, Address: wallet.MustParseAddress(contactAddress)}))
+	require.NoError(t, s.Add(Entry{Name: "Alice", Kind: KindContact, Address: wallet.MustParseAddress(contactAddress)}))
+	require.NoError(t, s.Add(Entry{Name: "cold", Kind: KindWallet, Address: wallet.MustParseAddress(wallettest.Address)}))
+
+	var names []string
+	for _, e := range s.List() {
+		names = append(names, e.Name)
+	}
+	assert.Equal(t, []string{"cold", "Alice", "bob"}, names)
+}
+
+func Test_Store_Add(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+		err   error
+	}{
+		{
+			name:  "duplicate name",
+			entry: Entry{Name: "ALICE", Kind: KindContact, Address: wallet.MustParseAddress(contactAddress)},
+			err:   ErrExists,
+		},
+		{
+			name:  "invalid name",
+			entry: Entry{Name: "@alice", Kind: KindContact, Address: wallet.MustParseAddress(contactAddress)},
+			err:   ErrInvalidName,
+		},
+		{
+			name:  "blank name",
+			entry: Entry{Kind: KindContact, Address: wallet.MustParseAddress(contactAddress)},
+			err:   ErrInvalidName,
+		},
+		{
+			name:  "unknown kind",
+			entry: Entry{Name: "bob", Kind: "friend", Address: wallet.MustParseAddress(contactAddress)},
+		},
+		{
+			name:  "zero address",
+			entry: Entry{Name: "bob", Kind: KindContact},
+		},
+		{
+			name:  "keystore",
+			entry: Entry{Name: "bob", Kind: KindWallet, Address: wallet.MustParseAddress(wallettest.Address), Keystore: "keystore.json"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, cleanup := newTestStore(t)
+			defer cleanup()
+			require.NoError(t, s.Add(Entry{Name: "alice", Kind: KindContact, Address: wallet.MustParseAddress(contactAddress)}))
+
+			err := s.Add(test.entry)
+
+			require.Error(t, err)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err), "expected %v, got %v", test.err, err)
+			}
+			assert.Len(t, s.List(), 1)
+		})
+	}
+}
+
+func Test_Store_AddKeystore(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+
+	w := wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+	path, err := w.WriteKeystoreFile(filepath.Join(s.Dir(), "..", "elsewhere"), "passphrase", wallet.KeystoreOptions{KDF: wallet.KDFScrypt, ScryptN: 1 << 4, ScryptP: 1})
+	require.NoError(t, err)
+
+	require.NoError(t, s.AddKeystore(Entry{Name: "hot", Kind: KindWallet}, path))
+
+	e, err := s.Get("@hot")
+	require.NoError(t, err)
+	assert.Equal(t, wallettest.Address, e.Address.Hex())
+	assert.True(t, e.IsKeystoreBacked())
+
+	// The copy in the store must still decrypt to the wallet.
+	w2, err := wallet.FromKeystoreFile(s.KeystorePath(e), "passphrase")
+	require.NoError(t, err)
+	assert.NoError(t, w.Equals(w2))
+
+	assert.True(t, errors.Is(s.AddKeystore(Entry{Name: "hot2", Kind: KindWallet}, path), ErrExists))
+	assert.Error(t, s.AddKeystore(Entry{Name: "contact", Kind: KindContact}, path))
+}
+
+func Test_Store_AddKeystore_KeepsRemovedKeystore(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+	opts := wallet.KeystoreOptions{KDF: wallet.KDFScrypt, ScryptN: 1 << 4, ScryptP: 1}
+
+	w := wallet.MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+	keystoreBytes, err := w.EncryptKeystore("passphrase", opts)
+	require.NoError(t, err)
+	path := filepath.Join(s.Dir(), "..", "main", "keystore.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, ioutil.WriteFile(path, keystoreBytes, 0600))
+	require.NoError(t, s.AddKeystore(Entry{Name: "main", Kind: KindWallet}, path))
+	e, err := s.Remove("main")
+	require.NoError(t, err)
+
+	// Another wallet's keystore with the same file name must not replace the removed wallet's copy.
+	other, err := wallet.New()
+	require.NoError(t, err)
+	otherBytes, err := other.EncryptKeystore("passphrase", opts)
+	require.NoError(t, err)
+	otherPath := filepath.Join(s.Dir(), "..", "other", "keystore.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(otherPath), 0700))
+	require.NoError(t, ioutil.WriteFile(otherPath, otherBytes, 0600))
+	assert.True(t, errors.Is(s.AddKeystore(Entry{Name: "other", Kind: KindWallet}, otherPath), ErrExists))
+
+	w2, err := wallet.FromKeystoreFile(s.KeystorePath(e), "passphrase")
+	require.NoError(t, err)
+	assert.NoError(t, w.Equals(w2))
+}
+
+func Test_Store_CheckName(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+	require.NoError(t, s.Add(Entry{Name: "alice", Kind: KindContact, Address: wallet.MustParseAddress(contactAddress)}))
+
+	tests := []struct {
+		name string
+		s    string
+		err  error
+	}{
+		{name: "free", s: "bob"},
+		{name: "taken", s: "alice", err: ErrExists},
+		{name: "taken, casing", s: "ALICE", err: ErrExists},
+		{name: "invalid", s: "not valid", err: ErrInvalidName},
+		{name: "blank", s: "", err: ErrInvalidName},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := s.CheckName(test.s)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err), "expected %v, got %v", test.err, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_Store_Rename(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+	require.NoError(t, s.Add(Entry{Name: "alice", Kind: KindContact, Address: wallet.MustParseAddress(contactAddress)}))
+	require.NoError(t, s.Add(Entry{Name: "bob", Kind: KindContact, Address: wallet.MustParseAddress(contactAddress)}))
+
+	assert.True(t, errors.Is(s.Rename("alice", "Bob"), ErrExists))
+	assert.True(t, errors.Is(s.Rename("carol", "dave"), ErrNotFound))
+	assert.True(t, errors.Is(s.Rename("alice", "not valid"), ErrInvalidName))
+
+	require.NoError(t, s.Rename("@alice", "@Alice2"))
+	_, err := s.Get("alice")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	e, err := s.Get("alice2")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice2", e.Name)
+
+	require.NoError(t, s.Rename("bob", "BOB"))
+}
+
+func Test_Store_Remove(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+	require.NoError(t, s.Add(Entry{Name: "alice", Kind: KindContact, Address: wallet.MustParseAddress(contactAddress)}))
+
+	e, err := s.Remove("@alice")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", e.Name)
+	assert.Empty(t, s.List())
+
+	_, err = s.Remove("alice")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func Test_Store_Resolve(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+	require.NoError(t, s.Add(Entry{Name: "alice", Kind: KindContact, Address: wallet.MustParseAddress(contactAddress), Label: "Alice"}))
+
+	tests := []struct {
+		name    string
+		ref     string
+		address string
+		display string
+		err     bool
+	}{
+		{
+			name:    "name",
+			ref:     "@alice",
+			address: contactAddress,
+			display: contactAddress + " (Alice)",
+		},
+		{
+			name:    "stored address",
+			ref:     contactAddress,
+			address: contactAddress,
+			display: contactAddress + " (Alice)",
+		},
+		{
+			name:    "unknown address",
+			ref:     wallettest.Address,
+			address: wallettest.Address,
+			display: wallettest.Address,
+		},
+		{
+			name: "unknown name",
+			ref:  "@bob",
+			err:  true,
+		},
+		{
+			name: "not an address",
+			ref:  "alice",
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, e, err := s.Resolve(test.ref)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.address, a.Hex())
+			assert.Equal(t, test.display, s.Display(a))
+			assert.Equal(t, test.address == contactAddress, e != nil)
+		})
+	}
+}
+
+func Test_DefaultDir(t *testing.T) {
+	old, ok := os.LookupEnv(HomeEnv)
+	defer func() {
+		if ok {
+			_ = os.Setenv(HomeEnv, old)
+		} else {
+			_ = os.Unsetenv(HomeEnv)
+		}
+	}()
+
+	require.NoError(t, os.Setenv(HomeEnv, "/tmp/jeth-home"))
+	dir, err := DefaultDir()
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/jeth-home", dir)
+}
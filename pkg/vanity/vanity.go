@@ -0,0 +1,137 @@
+package vanity
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Insulince/jeth/pkg/wallet"
+
+	"github.com/pkg/errors"
+)
+
+// Pattern describes the addresses a vanity search is looking for. Every non-empty part of the pattern must match.
+// Prefix, Suffix, and Regex are all matched against the 40 character hexadecimal body of an address, without its "0x".
+type Pattern struct {
+	Prefix string
+	Suffix string
+	// Regex is matched against the EIP-55 checksummed address body, use "(?i)" to make it case-insensitive.
+	Regex *regexp.Regexp
+	// CaseSensitive requires Prefix and Suffix to match the EIP-55 checksummed casing of the address exactly, which is far harder than a case-insensitive match.
+	CaseSensitive bool
+}
+
+// Validate checks that p can ever match an address.
+func (p Pattern) Validate() error {
+	if p.Prefix == "" && p.Suffix == "" && p.Regex == nil {
+		return errors.New("pattern must have at least one of a prefix, suffix, or regex")
+	}
+	if len(p.Prefix)+len(p.Suffix) > 40 {
+		return fmt.Errorf("prefix and suffix are %v characters combined, but an address only has 40", len(p.Prefix)+len(p.Suffix))
+	}
+	for _, part := range []string{p.Prefix, p.Suffix} {
+		for _, c := range part {
+			if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+				return fmt.Errorf("\"%s\" contains \"%c\" which is not a hexadecimal character and can never appear in an address", part, c)
+			}
+		}
+	}
+	return nil
+}
+
+// Matches reports whether address, a "0x" prefixed EIP-55 checksummed address such as wallet.Wallet.Address returns, matches p.
+func (p Pattern) Matches(address string) bool {
+	body := strings.TrimPrefix(address, "0x")
+
+	prefix, suffix, comparable := p.Prefix, p.Suffix, body
+	if !p.CaseSensitive {
+		prefix, suffix, comparable = strings.ToLower(prefix), strings.ToLower(suffix), strings.ToLower(body)
+	}
+
+	if !strings.HasPrefix(comparable, prefix) || !strings.HasSuffix(comparable, suffix) {
+		return false
+	}
+	if p.Regex != nil && !p.Regex.MatchString(body) {
+		return false
+	}
+	return true
+}
+
+// Difficulty returns the expected number of attempts needed to find a match for p's prefix and suffix.
+// Each hexadecimal character is 1 in 16, and when matching case-sensitively each letter is a further 1 in 2 since EIP-55 casing is effectively random.
+// The difficulty of a regex cannot be computed in general, so it is not accounted for, in that case treat the result as a lower bound.
+func (p Pattern) Difficulty() float64 {
+	difficulty := math.Pow(16, float64(len(p.Prefix)+len(p.Suffix)))
+	if p.CaseSensitive {
+		for _, c := range p.Prefix + p.Suffix {
+			if strings.ContainsRune("abcdefABCDEF", c) {
+				difficulty *= 2
+			}
+		}
+	}
+	return difficulty
+}
+
+// Search generates random wallets across workers goroutines until one's address matches p, the matching wallet is returned.
+// attempts, if not nil, is atomically incremented for every wallet generated so that the caller can report progress while the search runs.
+// If ctx is cancelled before a match is found, ctx.Err() is returned.
+func Search(ctx context.Context, p Pattern, workers int, attempts *uint64) (*wallet.Wallet, error) {
+	if err := p.Validate(); err != nil {
+		return nil, errors.Wrap(err, "validating pattern")
+	}
+	if workers < 1 {
+		return nil, fmt.Errorf("must search with at least 1 worker, got %v", workers)
+	}
+	if attempts == nil {
+		attempts = new(uint64)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	found := make(chan *wallet.Wallet, 1)
+	failed := make(chan error, 1)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				w, err := wallet.New()
+				if err != nil {
+					select {
+					case failed <- err:
+					default:
+					}
+					cancel()
+					return
+				}
+				atomic.AddUint64(attempts, 1)
+				if !p.Matches(w.Address()) {
+					continue
+				}
+				// Only the first match is kept, any others found in the meantime are dropped.
+				select {
+				case found <- w:
+				default:
+				}
+				cancel()
+				return
+			}
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case w := <-found:
+		return w, nil
+	case err := <-failed:
+		return nil, errors.Wrap(err, "generating wallet")
+	default:
+		return nil, ctx.Err()
+	}
+}
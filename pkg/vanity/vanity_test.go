@@ -0,0 +1,72 @@
+package vanity
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+)
+
+func Test_Pattern_Matches(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern Pattern
+		matches bool
+	}{
+		{name: "prefix", pattern: Pattern{Prefix: "1932"}, matches: true},
+		{name: "prefix wrong", pattern: Pattern{Prefix: "1933"}, matches: false},
+		{name: "suffix", pattern: Pattern{Suffix: "12f6"}, matches: true},
+		{name: "prefix and suffix", pattern: Pattern{Prefix: "19325d2d", Suffix: "f6"}, matches: true},
+		{name: "case-sensitive", pattern: Pattern{Prefix: "19325d2D", CaseSensitive: true}, matches: true},
+		{name: "case-sensitive wrong case", pattern: Pattern{Prefix: "19325d2d", CaseSensitive: true}, matches: false},
+		{name: "regex", pattern: Pattern{Regex: regexp.MustCompile("^1932.*F6$")}, matches: true},
+		{name: "regex wrong", pattern: Pattern{Regex: regexp.MustCompile("^dead")}, matches: false},
+		{name: "regex and prefix", pattern: Pattern{Prefix: "19", Regex: regexp.MustCompile("AF10")}, matches: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.matches, test.pattern.Matches(wallettest.Address))
+		})
+	}
+}
+
+func Test_Pattern_Validate(t *testing.T) {
+	assert.NoError(t, Pattern{Prefix: "dead", Suffix: "BEEF"}.Validate())
+	assert.Error(t, Pattern{}.Validate())
+	assert.Error(t, Pattern{Prefix: "jeth"}.Validate())
+	assert.Error(t, Pattern{Prefix: "0x00"}.Validate())
+}
+
+func Test_Pattern_Difficulty(t *testing.T) {
+	assert.Equal(t, 16.0, Pattern{Prefix: "a"}.Difficulty())
+	assert.Equal(t, 65536.0, Pattern{Prefix: "de", Suffix: "ad"}.Difficulty())
+	assert.Equal(t, 32.0, Pattern{Prefix: "a", CaseSensitive: true}.Difficulty())
+	assert.Equal(t, 16.0, Pattern{Prefix: "0", CaseSensitive: true}.Difficulty())
+}
+
+func Test_Search(t *testing.T) {
+	var attempts uint64
+	p := Pattern{Prefix: "a"}
+
+	w, err := Search(context.Background(), p, 4, &attempts)
+	require.NoError(t, err)
+
+	assert.True(t, p.Matches(w.Address()))
+	assert.NoError(t, w.Validate())
+	assert.NotZero(t, attempts)
+}
+
+func Test_Search_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// 40 characters is effectively impossible to find, so only cancellation can end the search.
+	_, err := Search(ctx, Pattern{Prefix: "0000000000000000000000000000000000000000"}, 2, nil)
+
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,94 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// Address is a validated 20 byte Ethereum address. Its zero value is the zero address, 0x0000000000000000000000000000000000000000.
+// *Address implements flag.Value, encoding.TextMarshaler, and encoding.TextUnmarshaler, so it can be used directly as a command line flag or JSON field.
+type Address common.Address
+
+// ParseAddress parses s, which must be "0x" followed by 40 hexadecimal characters.
+// All lowercase and all uppercase addresses carry no checksum and are accepted as-is, mixed case addresses must match their EIP-55 checksum.
+// The returned error wraps ErrInvalidLength, ErrInvalidHex, or ErrInvalidChecksum depending on what is wrong with s.
+func ParseAddress(s string) (Address, error) {
+	if len(s) != addressHexLength || !strings.HasPrefix(s, "0x") {
+		return Address{}, errors.Wrapf(ErrInvalidLength, "address must be \"0x\" followed by %v hexadecimal characters, got \"%s\"", addressHexLength-2, s)
+	}
+
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return Address{}, errors.Wrap(ErrInvalidHex, err.Error())
+	}
+
+	var a Address
+	copy(a[:], b)
+
+	// A typo in a mixed case address almost certainly breaks its checksum, which is the whole point of EIP-55, so never let one through.
+	digits := s[2:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && s != a.Hex() {
+		return Address{}, errors.Wrapf(ErrInvalidChecksum, "got \"%s\", expected \"%s\"", s, a.Hex())
+	}
+
+	return a, nil
+}
+
+// MustParseAddress calls ParseAddress and panics if it returns an error.
+func MustParseAddress(s string) Address {
+	a, err := ParseAddress(s)
+	if err != nil {
+		panic(errors.Wrap(err, "must parse address"))
+	}
+	return a
+}
+
+// AddressFromPublicKey derives the Address of publicKey.
+func AddressFromPublicKey(publicKey *ecdsa.PublicKey) Address {
+	return Address(ethcrypto.PubkeyToAddress(*publicKey))
+}
+
+// Hex returns a in its EIP-55 checksummed hexadecimal format.
+func (a Address) Hex() string {
+	return common.Address(a).Hex()
+}
+
+// String returns a in its EIP-55 checksummed hexadecimal format.
+func (a Address) String() string {
+	return a.Hex()
+}
+
+// Common returns a as go-ethereum's common.Address.
+func (a Address) Common() common.Address {
+	return common.Address(a)
+}
+
+// IsZero reports whether a is the zero address. Funds sent to the zero address are lost, so it usually means an address was never set.
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// Set parses s into a, see ParseAddress. It lets *Address be used as a flag.Value.
+func (a *Address) Set(s string) error {
+	parsed, err := ParseAddress(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// MarshalText encodes a in its EIP-55 checksummed hexadecimal format.
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.Hex()), nil
+}
+
+// UnmarshalText parses text into a, see ParseAddress.
+func (a *Address) UnmarshalText(text []byte) error {
+	return a.Set(string(text))
+}
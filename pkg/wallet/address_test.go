@@ -0,0 +1,104 @@
+package wallet
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+)
+
+func Test_ParseAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		err     error
+	}{
+		{
+			name:    "checksummed",
+			address: wallettest.Address,
+		},
+		{
+			name:    "lowercase",
+			address: strings.ToLower(wallettest.Address),
+		},
+		{
+			name:    "uppercase",
+			address: "0x" + strings.ToUpper(wallettest.Address[2:]),
+		},
+		{
+			name:    "bad checksum",
+			address: "0x19325d2D5c17AF1096D28A12850D27bD182612f6",
+			err:     ErrInvalidChecksum,
+		},
+		{
+			name:    "no prefix",
+			address: wallettest.Address[2:],
+			err:     ErrInvalidLength,
+		},
+		{
+			name:    "too short",
+			address: wallettest.Address[:41],
+			err:     ErrInvalidLength,
+		},
+		{
+			name:    "not hex",
+			address: "0x" + strings.Repeat("z", 40),
+			err:     ErrInvalidHex,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, err := ParseAddress(test.address)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err), "expected %v, got %v", test.err, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, wallettest.Address, a.Hex())
+			assert.Equal(t, wallettest.Address, a.String())
+			assert.False(t, a.IsZero())
+		})
+	}
+}
+
+func Test_AddressFromPublicKey(t *testing.T) {
+	a := AddressFromPublicKey(MustPublicKeyHexToECDSA(wallettest.PublicKeyHex))
+
+	assert.Equal(t, MustParseAddress(wallettest.Address), a)
+}
+
+func Test_Address_Set(t *testing.T) {
+	var a Address
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.Var(&a, "address", "")
+
+	require.NoError(t, fs.Parse([]string{"-address", strings.ToLower(wallettest.Address)}))
+	assert.Equal(t, wallettest.Address, a.Hex())
+
+	assert.Error(t, fs.Parse([]string{"-address", "0x19325d2D5c17AF1096D28A12850D27bD182612f6"}))
+}
+
+func Test_Address_MarshalText(t *testing.T) {
+	type doc struct {
+		Address Address `json:"address"`
+	}
+
+	b, err := json.Marshal(doc{Address: MustParseAddress(strings.ToLower(wallettest.Address))})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"address": "`+wallettest.Address+`"}`, string(b))
+
+	var d doc
+	require.NoError(t, json.Unmarshal(b, &d))
+	assert.Equal(t, wallettest.Address, d.Address.Hex())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"address": "0x1234"}`), &d))
+}
@@ -0,0 +1,87 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// base58Alphabet is the Bitcoin base58 alphabet, which omits 0, O, I, and l to avoid visual ambiguity.
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base58Radix = big.NewInt(58)
+
+// base58CheckEncode appends a 4 byte double SHA256 checksum to b and base58 encodes the result.
+func base58CheckEncode(b []byte) string {
+	return base58Encode(append(append([]byte{}, b...), base58Checksum(b)...))
+}
+
+// base58CheckDecode base58 decodes s and verifies and strips its 4 byte double SHA256 checksum.
+func base58CheckDecode(s string) ([]byte, error) {
+	b, err := base58Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 4 {
+		return nil, errors.New("base58 string is too short to contain a checksum")
+	}
+
+	payload, checksum := b[:len(b)-4], b[len(b)-4:]
+	if !bytes.Equal(base58Checksum(payload), checksum) {
+		return nil, errors.New("base58 checksum is invalid")
+	}
+
+	return payload, nil
+}
+
+func base58Checksum(b []byte) []byte {
+	first := sha256.Sum256(b)
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
+
+func base58Encode(b []byte) string {
+	x := new(big.Int).SetBytes(b)
+	mod := new(big.Int)
+
+	var out []byte
+	for x.Sign() > 0 {
+		x.DivMod(x, base58Radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	// Each leading zero byte is encoded as a leading "1".
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+
+	// Digits were produced least significant first.
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	return string(out)
+}
+
+func base58Decode(s string) ([]byte, error) {
+	x := new(big.Int)
+	for _, c := range []byte(s) {
+		digit := bytes.IndexByte([]byte(base58Alphabet), c)
+		if digit < 0 {
+			return nil, errors.Errorf("invalid base58 character %q", c)
+		}
+		x.Mul(x, base58Radix)
+		x.Add(x, big.NewInt(int64(digit)))
+	}
+
+	leadingZeros := 0
+	for leadingZeros < len(s) && s[leadingZeros] == base58Alphabet[0] {
+		leadingZeros++
+	}
+
+	return append(make([]byte, leadingZeros), x.Bytes()...), nil
+}
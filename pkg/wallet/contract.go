@@ -0,0 +1,34 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// CreateAddress returns the address of the contract a deploys with a regular CREATE, either a contract creation transaction or the CREATE opcode, at nonce.
+// For a wallet nonce is its transaction count at the time of deployment, for a contract it is the number of contracts it has created plus one (EIP-161).
+func (a Address) CreateAddress(nonce uint64) Address {
+	return Address(ethcrypto.CreateAddress(common.Address(a), nonce))
+}
+
+// Create2Address returns the address of the contract a deploys with the CREATE2 opcode (EIP-1014) given salt and initCodeHash, the Keccak-256 hash of the contract's init code.
+// Unlike CreateAddress it does not depend on a nonce, so it is known before deployment however many transactions a sends in between. Only contracts can execute CREATE2, so a is usually a factory contract.
+func (a Address) Create2Address(salt [32]byte, initCodeHash []byte) (Address, error) {
+	if len(initCodeHash) != common.HashLength {
+		return Address{}, fmt.Errorf("init code hash must be %v bytes, got %v", common.HashLength, len(initCodeHash))
+	}
+
+	return Address(ethcrypto.CreateAddress2(common.Address(a), salt, initCodeHash)), nil
+}
+
+// InitCodeHash returns the Keccak-256 hash of initCode, as Create2Address expects.
+func InitCodeHash(initCode []byte) []byte {
+	return ethcrypto.Keccak256(initCode)
+}
+
+// CreateAddress returns the address of the contract w deploys with a contract creation transaction at nonce, see Address.CreateAddress.
+func (w *Wallet) CreateAddress(nonce uint64) Address {
+	return w.Addr().CreateAddress(nonce)
+}
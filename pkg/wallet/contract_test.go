@@ -0,0 +1,105 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+)
+
+func Test_Address_CreateAddress(t *testing.T) {
+	sender := MustParseAddress("0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0")
+
+	tests := []struct {
+		nonce    uint64
+		expected string
+	}{
+		{nonce: 0, expected: "0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d"},
+		{nonce: 1, expected: "0x343c43a37d37dff08ae8c4a11544c718abb4fcf8"},
+		{nonce: 2, expected: "0xf778b86fa74e846c4f0a1fbd1335fe81c00a0c91"},
+		{nonce: 3, expected: "0xfffd933a0bc612844eaf0c6fe3e5b8e9b6c1d19c"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			assert.Equal(t, MustParseAddress(test.expected), sender.CreateAddress(test.nonce))
+		})
+	}
+}
+
+func Test_Wallet_CreateAddress(t *testing.T) {
+	w := MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+
+	assert.Equal(t, MustParseAddress(wallettest.Address).CreateAddress(7), w.CreateAddress(7))
+}
+
+func Test_Address_Create2Address(t *testing.T) {
+	// These are the examples from EIP-1014.
+	tests := []struct {
+		deployer string
+		salt     string
+		initCode string
+		expected string
+	}{
+		{
+			deployer: "0x0000000000000000000000000000000000000000",
+			salt:     "0x0000000000000000000000000000000000000000000000000000000000000000",
+			initCode: "0x00",
+			expected: "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38",
+		},
+		{
+			deployer: "0xdeadbeef00000000000000000000000000000000",
+			salt:     "0x0000000000000000000000000000000000000000000000000000000000000000",
+			initCode: "0x00",
+			expected: "0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3",
+		},
+		{
+			deployer: "0xdeadbeef00000000000000000000000000000000",
+			salt:     "0x000000000000000000000000feed000000000000000000000000000000000000",
+			initCode: "0x00",
+			expected: "0xD04116cDd17beBE565EB2422F2497E06cC1C9833",
+		},
+		{
+			deployer: "0x0000000000000000000000000000000000000000",
+			salt:     "0x0000000000000000000000000000000000000000000000000000000000000000",
+			initCode: "0xdeadbeef",
+			expected: "0x70f2b2914A2a4b783FaEFb75f459A580616Fcb5e",
+		},
+		{
+			deployer: "0x00000000000000000000000000000000deadbeef",
+			salt:     "0x00000000000000000000000000000000000000000000000000000000cafebabe",
+			initCode: "0xdeadbeef",
+			expected: "0x60f3f640a8508fC6a86d45DF051962668E1e8AC7",
+		},
+		{
+			deployer: "0x00000000000000000000000000000000deadbeef",
+			salt:     "0x00000000000000000000000000000000000000000000000000000000cafebabe",
+			initCode: "0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			expected: "0x1d8bfDC5D46DC4f61D6b6115972536eBE6A8854C",
+		},
+		{
+			deployer: "0x0000000000000000000000000000000000000000",
+			salt:     "0x0000000000000000000000000000000000000000000000000000000000000000",
+			initCode: "0x",
+			expected: "0xE33C0C7F7df4809055C3ebA6c09CFe4BaF1BD9e0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			var salt [32]byte
+			copy(salt[:], common.FromHex(test.salt))
+
+			a, err := MustParseAddress(test.deployer).Create2Address(salt, InitCodeHash(hexutil.MustDecode(test.initCode)))
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, a.Hex())
+		})
+	}
+
+	_, err := Address{}.Create2Address([32]byte{}, []byte{0x00})
+	assert.Error(t, err)
+}
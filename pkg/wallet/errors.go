@@ -0,0 +1,36 @@
+package wallet
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	// privateKeyHexLength is the length of a private key in hexadecimal format, 32 bytes.
+	privateKeyHexLength = 64
+	// publicKeyHexLength is the length of a public key in hexadecimal format, 64 bytes (the uncompressed point without its "04" prefix).
+	publicKeyHexLength = 128
+	// addressHexLength is the length of an address in hexadecimal format, "0x" followed by 20 bytes.
+	addressHexLength = 42
+)
+
+// Sentinel errors returned (wrapped) by this package's constructors and parsers, check for them with errors.Is.
+var (
+	// ErrInvalidLength means a hexadecimal key or address is not the length its format requires.
+	ErrInvalidLength = errors.New("invalid length")
+	// ErrInvalidHex means a key or address contains characters which are not hexadecimal.
+	ErrInvalidHex = errors.New("invalid hexadecimal")
+	// ErrInvalidChecksum means a mixed case address does not match its EIP-55 checksum, it was most likely mistyped.
+	ErrInvalidChecksum = errors.New("invalid address checksum")
+	// ErrInvalidPrivateKey means a private key is missing, zero, or not less than the order of secp256k1.
+	ErrInvalidPrivateKey = errors.New("invalid private key")
+	// ErrOffCurve means a public key is missing or is not a point on the secp256k1 curve.
+	ErrOffCurve = errors.New("public key is not a point on the secp256k1 curve")
+	// ErrPrivateKeyMismatch means two wallets have different private keys.
+	ErrPrivateKeyMismatch = errors.New("private keys are not equal")
+	// ErrPublicKeyMismatch means two wallets have different public keys, or a public key does not belong to its private key.
+	ErrPublicKeyMismatch = errors.New("public keys are not equal")
+	// ErrAddressMismatch means two wallets have different addresses, or an address does not belong to its public key.
+	ErrAddressMismatch = errors.New("addresses are not equal")
+	// ErrWatchOnly means a wallet has no private key, so it cannot sign or be exported.
+	ErrWatchOnly = errors.New("wallet is watch-only")
+)
@@ -0,0 +1,349 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// HardenedKeyStart is the first hardened child index, written as i' or iH in derivation paths.
+// Hardened children can only be derived from an extended private key.
+const HardenedKeyStart = 0x80000000
+
+var (
+	// DefaultDerivationPath is the BIP-44 path of the first Ethereum account, m/44'/60'/0'/0/0. It is what MetaMask, Ledger, MEW, etc. derive from a mnemonic by default.
+	DefaultDerivationPath = accounts.DefaultBaseDerivationPath
+	// DefaultAccountPath is the BIP-44 path that Ethereum account addresses are children of, m/44'/60'/0'/0.
+	// Its extended public key can derive every account address without access to any private key.
+	DefaultAccountPath = accounts.DefaultRootDerivationPath
+)
+
+var (
+	// xprvVersion and xpubVersion are the mainnet version bytes which make serialized keys start with "xprv" and "xpub".
+	xprvVersion = []byte{0x04, 0x88, 0xad, 0xe4}
+	xpubVersion = []byte{0x04, 0x88, 0xb2, 0x1e}
+
+	// masterKeySecret is the HMAC key BIP-32 uses to derive a master key from a seed.
+	masterKeySecret = []byte("Bitcoin seed")
+)
+
+// extendedKeyLength is the length of a serialized extended key before its checksum: version (4) || depth (1) || parent fingerprint (4) || child number (4) || chain code (32) || key (33).
+const extendedKeyLength = 78
+
+// ExtendedKey is a BIP-32 hierarchical deterministic key, a key plus the chain code needed to derive child keys from it.
+// An extended private key can derive any child, its extended public key counterpart (see Neuter) can only derive non-hardened children, and only their public keys and addresses.
+// This makes extended public keys ideal for generating deposit addresses on machines which should never hold a private key.
+type ExtendedKey struct {
+	// key is the 32 byte private key for an extended private key, or the 33 byte compressed public key for an extended public key.
+	key               []byte
+	chainCode         []byte
+	depth             uint8
+	parentFingerprint []byte
+	childNumber       uint32
+	isPrivate         bool
+}
+
+// NewMasterKey derives the BIP-32 master extended private key from seed (i.e. the output of MnemonicToSeed).
+func NewMasterKey(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed must be between 16 and 64 bytes, got %v", len(seed))
+	}
+
+	mac := hmac.New(sha512.New, masterKeySecret)
+	_, _ = mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	key, chainCode := sum[:32], sum[32:]
+	if !isValidPrivateKey(key) {
+		return nil, errors.New("seed produces an invalid master key, use a different seed")
+	}
+
+	k := &ExtendedKey{
+		key:               key,
+		chainCode:         chainCode,
+		depth:             0,
+		parentFingerprint: []byte{0, 0, 0, 0},
+		childNumber:       0,
+		isPrivate:         true,
+	}
+
+	return k, nil
+}
+
+// ParseExtendedKey parses a base58 serialized "xprv" or "xpub" extended key, as produced by ExtendedKey.String.
+func ParseExtendedKey(s string) (*ExtendedKey, error) {
+	b, err := base58CheckDecode(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding base58")
+	}
+	if len(b) != extendedKeyLength {
+		return nil, fmt.Errorf("extended key must be %v bytes, got %v", extendedKeyLength, len(b))
+	}
+
+	k := &ExtendedKey{
+		depth:             b[4],
+		parentFingerprint: b[5:9],
+		childNumber:       binary.BigEndian.Uint32(b[9:13]),
+		chainCode:         b[13:45],
+	}
+
+	version, keyData := b[:4], b[45:]
+	switch {
+	case bytes.Equal(version, xprvVersion):
+		if keyData[0] != 0x00 {
+			return nil, errors.New("extended private key must be prefixed with 0x00")
+		}
+		if !isValidPrivateKey(keyData[1:]) {
+			return nil, errors.New("extended private key is out of range")
+		}
+		k.key = keyData[1:]
+		k.isPrivate = true
+	case bytes.Equal(version, xpubVersion):
+		if _, err := ethcrypto.DecompressPubkey(keyData); err != nil {
+			return nil, errors.Wrap(err, "decompressing extended public key")
+		}
+		k.key = keyData
+		k.isPrivate = false
+	default:
+		return nil, fmt.Errorf("unsupported extended key version %x, only xprv and xpub are supported", version)
+	}
+
+	if k.depth == 0 && (!bytes.Equal(k.parentFingerprint, []byte{0, 0, 0, 0}) || k.childNumber != 0) {
+		return nil, errors.New("master extended key must have a zero parent fingerprint and child number")
+	}
+
+	return k, nil
+}
+
+// IsPrivate reports whether k is an extended private key.
+func (k *ExtendedKey) IsPrivate() bool {
+	return k.isPrivate
+}
+
+// Depth returns how many derivations k is from the master key.
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+// ChildNumber returns the index k was derived at from its parent, HardenedKeyStart or above for hardened children.
+func (k *ExtendedKey) ChildNumber() uint32 {
+	return k.childNumber
+}
+
+// Child derives the child of k at index. Indexes of HardenedKeyStart and above derive hardened children, which requires k to be an extended private key.
+// In the astronomically unlikely case that index produces an invalid key, an error is returned and the next index should be used instead, as BIP-32 specifies.
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	hardened := index >= HardenedKeyStart
+	if hardened && !k.isPrivate {
+		return nil, fmt.Errorf("cannot derive hardened child %v from an extended public key", index)
+	}
+
+	compressedPublicKey, err := k.compressedPublicKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting compressed public key")
+	}
+
+	var data []byte
+	if hardened {
+		// Hardened children are derived from the parent private key, 0x00 pads it out to the same length as a compressed public key.
+		data = append([]byte{0x00}, k.key...)
+	} else {
+		data = append([]byte{}, compressedPublicKey...)
+	}
+	indexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(indexBytes, index)
+	data = append(data, indexBytes...)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	_, _ = mac.Write(data)
+	sum := mac.Sum(nil)
+	il, chainCode := sum[:32], sum[32:]
+
+	if !isValidPrivateKey(il) {
+		return nil, fmt.Errorf("child key %v is invalid, use the next index", index)
+	}
+
+	child := &ExtendedKey{
+		chainCode:         chainCode,
+		depth:             k.depth + 1,
+		parentFingerprint: fingerprint(compressedPublicKey),
+		childNumber:       index,
+		isPrivate:         k.isPrivate,
+	}
+
+	curve := ethcrypto.S256()
+	if k.isPrivate {
+		// Child private key = IL + parent private key (mod n).
+		childKey := new(big.Int).Add(new(big.Int).SetBytes(il), new(big.Int).SetBytes(k.key))
+		childKey.Mod(childKey, curve.Params().N)
+		if childKey.Sign() == 0 {
+			return nil, fmt.Errorf("child key %v is invalid, use the next index", index)
+		}
+		child.key = common.LeftPadBytes(childKey.Bytes(), 32)
+	} else {
+		// Child public key = IL*G + parent public key, which is why the private key is not needed.
+		parent, err := ethcrypto.DecompressPubkey(k.key)
+		if err != nil {
+			return nil, errors.Wrap(err, "decompressing parent public key")
+		}
+		ilx, ily := curve.ScalarBaseMult(il)
+		x, y := curve.Add(ilx, ily, parent.X, parent.Y)
+		if x.Sign() == 0 && y.Sign() == 0 {
+			return nil, fmt.Errorf("child key %v is invalid, use the next index", index)
+		}
+		child.key = ethcrypto.CompressPubkey(&ecdsa.PublicKey{Curve: curve, X: x, Y: y})
+	}
+
+	return child, nil
+}
+
+// Derive derives the descendant of k along path, one Child at a time. The path is relative to k, so it is usually called on a master key.
+// Paths can be parsed from their string form, e.g. "m/44'/60'/0'/0/0", with accounts.ParseDerivationPath.
+func (k *ExtendedKey) Derive(path accounts.DerivationPath) (*ExtendedKey, error) {
+	descendant := k
+	for _, index := range path {
+		child, err := descendant.Child(index)
+		if err != nil {
+			return nil, errors.Wrapf(err, "deriving child %v of path %s", index, path)
+		}
+		descendant = child
+	}
+	return descendant, nil
+}
+
+// Neuter returns the extended public key counterpart of k. If k is already an extended public key, k is returned.
+func (k *ExtendedKey) Neuter() (*ExtendedKey, error) {
+	if !k.isPrivate {
+		return k, nil
+	}
+
+	compressedPublicKey, err := k.compressedPublicKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting compressed public key")
+	}
+
+	neutered := &ExtendedKey{
+		key:               compressedPublicKey,
+		chainCode:         k.chainCode,
+		depth:             k.depth,
+		parentFingerprint: k.parentFingerprint,
+		childNumber:       k.childNumber,
+		isPrivate:         false,
+	}
+
+	return neutered, nil
+}
+
+// String returns the base58 serialization of k, starting with "xprv" for extended private keys and "xpub" for extended public keys.
+// Be careful with where an xprv is shared, it grants access to k and all of its descendants.
+func (k *ExtendedKey) String() string {
+	b := make([]byte, 0, extendedKeyLength)
+	if k.isPrivate {
+		b = append(b, xprvVersion...)
+	} else {
+		b = append(b, xpubVersion...)
+	}
+	b = append(b, k.depth)
+	b = append(b, k.parentFingerprint...)
+	childNumber := make([]byte, 4)
+	binary.BigEndian.PutUint32(childNumber, k.childNumber)
+	b = append(b, childNumber...)
+	b = append(b, k.chainCode...)
+	if k.isPrivate {
+		b = append(b, 0x00)
+	}
+	b = append(b, k.key...)
+
+	return base58CheckEncode(b)
+}
+
+// PublicKey returns the public key of k in ECDSA format.
+func (k *ExtendedKey) PublicKey() (*ecdsa.PublicKey, error) {
+	if k.isPrivate {
+		privateKey, err := ethcrypto.ToECDSA(k.key)
+		if err != nil {
+			return nil, errors.Wrap(err, "converting private key to ecdsa")
+		}
+		return &privateKey.PublicKey, nil
+	}
+	return ethcrypto.DecompressPubkey(k.key)
+}
+
+// Address returns the Ethereum address of k. It works for extended public keys too, which is how watch-only addresses are derived.
+func (k *ExtendedKey) Address() (string, error) {
+	publicKey, err := k.PublicKey()
+	if err != nil {
+		return "", errors.Wrap(err, "getting public key")
+	}
+	return ethcrypto.PubkeyToAddress(*publicKey).Hex(), nil
+}
+
+// Wallet returns the Wallet for k's private key. If k is an extended public key the returned Wallet is watch-only.
+func (k *ExtendedKey) Wallet() (*Wallet, error) {
+	if !k.isPrivate {
+		publicKey, err := ethcrypto.DecompressPubkey(k.key)
+		if err != nil {
+			return nil, errors.Wrap(err, "decompressing public key")
+		}
+		return WatchOnlyFromPublicKey(publicKey)
+	}
+
+	privateKey, err := ethcrypto.ToECDSA(k.key)
+	if err != nil {
+		return nil, errors.Wrap(err, "converting private key to ecdsa")
+	}
+
+	return FromPrivateKey(privateKey)
+}
+
+// FromSeed derives a new Wallet from seed (i.e. the output of MnemonicToSeed) at path.
+func FromSeed(seed []byte, path accounts.DerivationPath) (*Wallet, error) {
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, errors.Wrap(err, "deriving master key")
+	}
+
+	k, err := master.Derive(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "deriving key")
+	}
+
+	return k.Wallet()
+}
+
+// compressedPublicKey returns the 33 byte compressed public key of k.
+func (k *ExtendedKey) compressedPublicKey() ([]byte, error) {
+	if !k.isPrivate {
+		return k.key, nil
+	}
+	publicKey, err := k.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return ethcrypto.CompressPubkey(publicKey), nil
+}
+
+// fingerprint returns the first 4 bytes of the HASH160 (RIPEMD160 of SHA256) of a compressed public key, which identifies a parent key.
+func fingerprint(compressedPublicKey []byte) []byte {
+	sha := sha256.Sum256(compressedPublicKey)
+	h := ripemd160.New()
+	_, _ = h.Write(sha[:])
+	return h.Sum(nil)[:4]
+}
+
+// isValidPrivateKey checks that key is a scalar in [1, n) where n is the order of secp256k1.
+func isValidPrivateKey(key []byte) bool {
+	k := new(big.Int).SetBytes(key)
+	return k.Sign() > 0 && k.Cmp(ethcrypto.S256().Params().N) < 0
+}
@@ -0,0 +1,225 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hdTestChild struct {
+	index uint32
+	xprv  string
+	xpub  string
+}
+
+// hdTestVectors are the official test vectors 1, 2, and 3 from BIP-32. Each child is derived from the previous one.
+var hdTestVectors = []struct {
+	seed     string
+	xprv     string
+	xpub     string
+	children []hdTestChild
+}{
+	{
+		seed: "000102030405060708090a0b0c0d0e0f",
+		xprv: "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+		xpub: "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+		children: []hdTestChild{
+			{
+				index: HardenedKeyStart + 0,
+				xprv:  "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+				xpub:  "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+			},
+			{
+				index: 1,
+				xprv:  "xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+				xpub:  "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+			},
+			{
+				index: HardenedKeyStart + 2,
+				xprv:  "xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM",
+				xpub:  "xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+			},
+			{
+				index: 2,
+				xprv:  "xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334",
+				xpub:  "xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+			},
+			{
+				index: 1000000000,
+				xprv:  "xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76",
+				xpub:  "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+			},
+		},
+	},
+	{
+		seed: "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+		xprv: "xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U",
+		xpub: "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB",
+		children: []hdTestChild{
+			{
+				index: 0,
+				xprv:  "xprv9vHkqa6EV4sPZHYqZznhT2NPtPCjKuDKGY38FBWLvgaDx45zo9WQRUT3dKYnjwih2yJD9mkrocEZXo1ex8G81dwSM1fwqWpWkeS3v86pgKt",
+				xpub:  "xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH",
+			},
+			{
+				index: HardenedKeyStart + 2147483647,
+				xprv:  "xprv9wSp6B7kry3Vj9m1zSnLvN3xH8RdsPP1Mh7fAaR7aRLcQMKTR2vidYEeEg2mUCTAwCd6vnxVrcjfy2kRgVsFawNzmjuHc2YmYRmagcEPdU9",
+				xpub:  "xpub6ASAVgeehLbnwdqV6UKMHVzgqAG8Gr6riv3Fxxpj8ksbH9ebxaEyBLZ85ySDhKiLDBrQSARLq1uNRts8RuJiHjaDMBU4Zn9h8LZNnBC5y4a",
+			},
+			{
+				index: 1,
+				xprv:  "xprv9zFnWC6h2cLgpmSA46vutJzBcfJ8yaJGg8cX1e5StJh45BBciYTRXSd25UEPVuesF9yog62tGAQtHjXajPPdbRCHuWS6T8XA2ECKADdw4Ef",
+				xpub:  "xpub6DF8uhdarytz3FWdA8TvFSvvAh8dP3283MY7p2V4SeE2wyWmG5mg5EwVvmdMVCQcoNJxGoWaU9DCWh89LojfZ537wTfunKau47EL2dhHKon",
+			},
+			{
+				index: HardenedKeyStart + 2147483646,
+				xprv:  "xprvA1RpRA33e1JQ7ifknakTFpgNXPmW2YvmhqLQYMmrj4xJXXWYpDPS3xz7iAxn8L39njGVyuoseXzU6rcxFLJ8HFsTjSyQbLYnMpCqE2VbFWc",
+				xpub:  "xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL",
+			},
+			{
+				index: 2,
+				xprv:  "xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j",
+				xpub:  "xpub6FnCn6nSzZAw5Tw7cgR9bi15UV96gLZhjDstkXXxvCLsUXBGXPdSnLFbdpq8p9HmGsApME5hQTZ3emM2rnY5agb9rXpVGyy3bdW6EEgAtqt",
+			},
+		},
+	},
+	{
+		// This vector tests that leading zeros in private keys are retained.
+		seed: "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+		xprv: "xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6",
+		xpub: "xpub661MyMwAqRbcEZVB4dScxMAdx6d4nFc9nvyvH3v4gJL378CSRZiYmhRoP7mBy6gSPSCYk6SzXPTf3ND1cZAceL7SfJ1Z3GC8vBgp2epUt13",
+		children: []hdTestChild{
+			{
+				index: HardenedKeyStart + 0,
+				xprv:  "xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L",
+				xpub:  "xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y",
+			},
+		},
+	},
+}
+
+func Test_NewMasterKey(t *testing.T) {
+	for _, vector := range hdTestVectors {
+		t.Run(vector.seed, func(t *testing.T) {
+			seed, err := hex.DecodeString(vector.seed)
+			require.NoError(t, err)
+
+			k, err := NewMasterKey(seed)
+			require.NoError(t, err)
+			assert.Equal(t, vector.xprv, k.String())
+
+			pub, err := k.Neuter()
+			require.NoError(t, err)
+			assert.Equal(t, vector.xpub, pub.String())
+		})
+	}
+}
+
+func Test_ExtendedKey_Child(t *testing.T) {
+	for _, vector := range hdTestVectors {
+		t.Run(vector.seed, func(t *testing.T) {
+			seed, err := hex.DecodeString(vector.seed)
+			require.NoError(t, err)
+
+			k, err := NewMasterKey(seed)
+			require.NoError(t, err)
+
+			for _, child := range vector.children {
+				k, err = k.Child(child.index)
+				require.NoError(t, err)
+				assert.Equal(t, child.xprv, k.String())
+
+				pub, err := k.Neuter()
+				require.NoError(t, err)
+				assert.Equal(t, child.xpub, pub.String())
+			}
+		})
+	}
+}
+
+func Test_ExtendedKey_Child_Public(t *testing.T) {
+	// Public derivation from a parent xpub must arrive at the same xpub as neutering the privately derived child.
+	parent, err := ParseExtendedKey(hdTestVectors[1].xpub)
+	require.NoError(t, err)
+
+	child, err := parent.Child(0)
+	require.NoError(t, err)
+	assert.Equal(t, hdTestVectors[1].children[0].xpub, child.String())
+
+	_, err = child.Child(HardenedKeyStart)
+	assert.Error(t, err)
+
+	// An extended public key can only make a watch-only wallet.
+	w, err := child.Wallet()
+	require.NoError(t, err)
+	assert.True(t, w.IsWatchOnly())
+	assert.NoError(t, w.Validate())
+	_, err = w.SignHash(make([]byte, 32))
+	assert.True(t, errors.Is(err, ErrWatchOnly))
+}
+
+func Test_ParseExtendedKey(t *testing.T) {
+	for _, vector := range hdTestVectors {
+		for _, s := range []string{vector.xprv, vector.xpub, vector.children[0].xprv, vector.children[0].xpub} {
+			t.Run(s, func(t *testing.T) {
+				k, err := ParseExtendedKey(s)
+				require.NoError(t, err)
+
+				assert.Equal(t, s, k.String())
+			})
+		}
+	}
+
+	_, err := ParseExtendedKey("xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHj")
+	assert.Error(t, err, "checksum must be verified")
+}
+
+func Test_ExtendedKey_Derive(t *testing.T) {
+	seed, err := MnemonicToSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	require.NoError(t, err)
+	master, err := NewMasterKey(seed)
+	require.NoError(t, err)
+
+	k, err := master.Derive(DefaultDerivationPath)
+	require.NoError(t, err)
+	w, err := k.Wallet()
+	require.NoError(t, err)
+	assert.Equal(t, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", w.Address())
+	assert.NoError(t, w.Validate())
+
+	// The account's xpub must derive the same addresses as the private keys without ever seeing them.
+	account, err := master.Derive(DefaultAccountPath)
+	require.NoError(t, err)
+	accountPub, err := account.Neuter()
+	require.NoError(t, err)
+	watchOnly, err := ParseExtendedKey(accountPub.String())
+	require.NoError(t, err)
+
+	for i := uint32(0); i < 5; i++ {
+		path, err := accounts.ParseDerivationPath(fmt.Sprintf("m/44'/60'/0'/0/%v", i))
+		require.NoError(t, err)
+
+		private, err := master.Derive(path)
+		require.NoError(t, err)
+		w, err := private.Wallet()
+		require.NoError(t, err)
+		assert.NoError(t, w.Validate())
+
+		public, err := watchOnly.Child(i)
+		require.NoError(t, err)
+		address, err := public.Address()
+		require.NoError(t, err)
+
+		assert.Equal(t, w.Address(), address)
+
+		publicW, err := public.Wallet()
+		require.NoError(t, err)
+		assert.Equal(t, w.Address(), publicW.Address())
+		assert.Equal(t, w.PublicKeyHex(), publicW.PublicKeyHex())
+	}
+}
@@ -0,0 +1,342 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KDF names the key derivation function used to stretch a passphrase into the encryption key of a keystore file.
+type KDF string
+
+const (
+	// KDFScrypt is the default KDF used by geth and MetaMask.
+	KDFScrypt KDF = "scrypt"
+	// KDFPBKDF2 is the alternate KDF permitted by the Web3 Secret Storage definition, always with HMAC-SHA256 as the PRF.
+	KDFPBKDF2 KDF = "pbkdf2"
+)
+
+const (
+	keystoreVersion = 3
+	keystoreCipher  = "aes-128-ctr"
+	keystorePRF     = "hmac-sha256"
+	keystoreDKLen   = 32
+	keystoreScryptR = 8
+)
+
+// KeystoreOptions controls how a Wallet's private key is encrypted into a keystore file.
+// Only the parameters belonging to KDF are used.
+type KeystoreOptions struct {
+	KDF              KDF
+	ScryptN          int
+	ScryptP          int
+	PBKDF2Iterations int
+}
+
+// Memory returns roughly how many bytes of memory encrypting or decrypting a keystore with o takes, scrypt's memory hardness, 0 for pbkdf2.
+func (o KeystoreOptions) Memory() int {
+	if o.KDF != KDFScrypt {
+		return 0
+	}
+	return 128 * keystoreScryptR * o.ScryptN
+}
+
+var (
+	// StandardKeystoreOptions matches the parameters geth uses for its own keystore files, ~1 second and 256MB of memory to decrypt.
+	StandardKeystoreOptions = KeystoreOptions{KDF: KDFScrypt, ScryptN: 1 << 18, ScryptP: 1, PBKDF2Iterations: 262144}
+	// LightKeystoreOptions matches geth's "light" parameters, much faster to decrypt but also much faster to brute force.
+	LightKeystoreOptions = KeystoreOptions{KDF: KDFScrypt, ScryptN: 1 << 12, ScryptP: 6, PBKDF2Iterations: 10240}
+)
+
+type (
+	keystoreJSON struct {
+		Address string             `json:"address"`
+		Crypto  keystoreCryptoJSON `json:"crypto"`
+		ID      string             `json:"id"`
+		Version int                `json:"version"`
+	}
+
+	keystoreCryptoJSON struct {
+		Cipher       string                   `json:"cipher"`
+		CipherText   string                   `json:"ciphertext"`
+		CipherParams keystoreCipherParamsJSON `json:"cipherparams"`
+		KDF          KDF                      `json:"kdf"`
+		KDFParams    keystoreKDFParamsJSON    `json:"kdfparams"`
+		MAC          string                   `json:"mac"`
+	}
+
+	keystoreCipherParamsJSON struct {
+		IV string `json:"iv"`
+	}
+
+	// keystoreKDFParamsJSON is the union of the scrypt and pbkdf2 parameter sets, the unused set is omitted when encoding.
+	keystoreKDFParamsJSON struct {
+		DKLen int    `json:"dklen"`
+		Salt  string `json:"salt"`
+		N     int    `json:"n,omitempty"`
+		R     int    `json:"r,omitempty"`
+		P     int    `json:"p,omitempty"`
+		C     int    `json:"c,omitempty"`
+		PRF   string `json:"prf,omitempty"`
+	}
+)
+
+// EncryptKeystore encrypts w's private key with passphrase into a version 3 Web3 Secret Storage (keystore) JSON document.
+// The result can be imported into geth, MetaMask, MEW, or any other wallet which understands keystore files.
+func (w *Wallet) EncryptKeystore(passphrase string, opts KeystoreOptions) ([]byte, error) {
+	if w.IsWatchOnly() {
+		return nil, ErrWatchOnly
+	}
+
+	salt := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, errors.Wrap(err, "generating salt")
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, errors.Wrap(err, "generating iv")
+	}
+
+	params := keystoreKDFParamsJSON{
+		DKLen: keystoreDKLen,
+		Salt:  hex.EncodeToString(salt),
+	}
+	switch opts.KDF {
+	case KDFScrypt:
+		params.N = opts.ScryptN
+		params.R = keystoreScryptR
+		params.P = opts.ScryptP
+	case KDFPBKDF2:
+		params.C = opts.PBKDF2Iterations
+		params.PRF = keystorePRF
+	default:
+		return nil, fmt.Errorf("unsupported kdf \"%s\"", opts.KDF)
+	}
+
+	derivedKey, err := deriveKeystoreKey(passphrase, opts.KDF, params)
+	if err != nil {
+		return nil, errors.Wrap(err, "deriving key from passphrase")
+	}
+
+	// Only the first half of the derived key is used for encryption, the second half is used to authenticate the ciphertext.
+	cipherText, err := aesCTRXOR(derivedKey[:16], ethcrypto.FromECDSA(w.privateKey), iv)
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypting private key")
+	}
+	mac := ethcrypto.Keccak256(derivedKey[16:32], cipherText)
+
+	id, err := newUUID()
+	if err != nil {
+		return nil, errors.Wrap(err, "generating id")
+	}
+
+	ks := keystoreJSON{
+		// Keystore files store the address in lowercase hexadecimal without the "0x" prefix.
+		Address: strings.ToLower(strings.TrimPrefix(w.Address(), "0x")),
+		Crypto: keystoreCryptoJSON{
+			Cipher:       keystoreCipher,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: keystoreCipherParamsJSON{IV: hex.EncodeToString(iv)},
+			KDF:          opts.KDF,
+			KDFParams:    params,
+			MAC:          hex.EncodeToString(mac),
+		},
+		ID:      id,
+		Version: keystoreVersion,
+	}
+
+	keystoreBytes, err := json.Marshal(ks)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling keystore json")
+	}
+
+	return keystoreBytes, nil
+}
+
+// FromKeystore decrypts keystoreBytes, a version 3 Web3 Secret Storage (keystore) JSON document, with passphrase and derives a new Wallet from the private key inside.
+// If the document records an address, it must match the derived address.
+func FromKeystore(keystoreBytes []byte, passphrase string) (*Wallet, error) {
+	var ks keystoreJSON
+	if err := json.Unmarshal(keystoreBytes, &ks); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling keystore json")
+	}
+
+	if ks.Version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version %v, only version %v is supported", ks.Version, keystoreVersion)
+	}
+	if ks.Crypto.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("unsupported cipher \"%s\", only \"%s\" is supported", ks.Crypto.Cipher, keystoreCipher)
+	}
+
+	mac, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding mac")
+	}
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding iv")
+	}
+	cipherText, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding ciphertext")
+	}
+
+	derivedKey, err := deriveKeystoreKey(passphrase, ks.Crypto.KDF, ks.Crypto.KDFParams)
+	if err != nil {
+		return nil, errors.Wrap(err, "deriving key from passphrase")
+	}
+
+	// A MAC mismatch almost always means the passphrase is wrong, it is checked before decrypting so that a wrong passphrase never produces a (wrong) key.
+	if !bytes.Equal(ethcrypto.Keccak256(derivedKey[16:32], cipherText), mac) {
+		return nil, errors.New("could not decrypt keystore with given passphrase, mac mismatch")
+	}
+
+	plainText, err := aesCTRXOR(derivedKey[:16], cipherText, iv)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting private key")
+	}
+
+	// Some older wallets wrote keys with leading zero bytes stripped, pad them back out to 32 bytes.
+	privateKeyBytes := common.LeftPadBytes(plainText, 32)
+	privateKey, err := ethcrypto.ToECDSA(privateKeyBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "converting decrypted private key to ecdsa")
+	}
+
+	w, err := FromPrivateKey(privateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "deriving wallet from decrypted private key")
+	}
+
+	if ks.Address != "" && !strings.EqualFold(strings.TrimPrefix(ks.Address, "0x"), strings.TrimPrefix(w.Address(), "0x")) {
+		return nil, errors.Wrapf(ErrAddressMismatch, "keystore address \"%s\" does not match address derived from its private key \"%s\"", ks.Address, w.Address())
+	}
+
+	return w, nil
+}
+
+// KeystoreAddress returns the address recorded in keystoreBytes, a version 3 Web3 Secret Storage (keystore) JSON document, without decrypting it.
+// The recorded address is not covered by the keystore's MAC, so it is only a claim until FromKeystore decrypts the document and checks it.
+func KeystoreAddress(keystoreBytes []byte) (Address, error) {
+	var ks keystoreJSON
+	if err := json.Unmarshal(keystoreBytes, &ks); err != nil {
+		return Address{}, errors.Wrap(err, "unmarshalling keystore json")
+	}
+
+	if ks.Address == "" {
+		return Address{}, errors.New("keystore does not record an address")
+	}
+
+	// Keystores record the address lowercase and usually without its "0x" prefix.
+	address, err := ParseAddress("0x" + strings.TrimPrefix(ks.Address, "0x"))
+	if err != nil {
+		return Address{}, errors.Wrap(err, "parsing keystore address")
+	}
+
+	return address, nil
+}
+
+// WriteKeystoreFile encrypts w with passphrase and writes it into dir using geth's "UTC--<created>--<address>" file naming convention.
+// The path to the written file is returned. The file is only readable by its owner.
+func (w *Wallet) WriteKeystoreFile(dir, passphrase string, opts KeystoreOptions) (string, error) {
+	keystoreBytes, err := w.EncryptKeystore(passphrase, opts)
+	if err != nil {
+		return "", errors.Wrap(err, "encrypting keystore")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", errors.Wrapf(err, "creating keystore directory \"%s\"", dir)
+	}
+
+	path := filepath.Join(dir, KeystoreFileName(w.Address(), time.Now()))
+	if err := ioutil.WriteFile(path, keystoreBytes, 0600); err != nil {
+		return "", errors.Wrapf(err, "writing keystore file \"%s\"", path)
+	}
+
+	return path, nil
+}
+
+// FromKeystoreFile reads the keystore file at path and calls FromKeystore on its contents.
+func FromKeystoreFile(path, passphrase string) (*Wallet, error) {
+	keystoreBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading keystore file \"%s\"", path)
+	}
+
+	return FromKeystore(keystoreBytes, passphrase)
+}
+
+// KeystoreFileName returns the file name geth would give the keystore file for address if it were created at t.
+func KeystoreFileName(address string, t time.Time) string {
+	t = t.UTC()
+	return fmt.Sprintf("UTC--%04d-%02d-%02dT%02d-%02d-%02d.%09dZ--%s",
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
+		strings.ToLower(strings.TrimPrefix(address, "0x")),
+	)
+}
+
+// deriveKeystoreKey stretches passphrase into a derived key using kdf and params.
+func deriveKeystoreKey(passphrase string, kdf KDF, params keystoreKDFParamsJSON) ([]byte, error) {
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding salt")
+	}
+	if params.DKLen < 32 {
+		return nil, fmt.Errorf("derived key length must be at least 32 bytes, got %v", params.DKLen)
+	}
+
+	switch kdf {
+	case KDFScrypt:
+		return scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, params.DKLen)
+	case KDFPBKDF2:
+		if params.PRF != keystorePRF {
+			return nil, fmt.Errorf("unsupported pbkdf2 prf \"%s\", only \"%s\" is supported", params.PRF, keystorePRF)
+		}
+		return pbkdf2.Key([]byte(passphrase), salt, params.C, params.DKLen, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("unsupported kdf \"%s\"", kdf)
+	}
+}
+
+// aesCTRXOR encrypts or decrypts in with AES-128 in CTR mode, the operation is symmetric.
+// The returned error wraps ErrInvalidLength if iv is not one AES block long.
+func aesCTRXOR(key, in, iv []byte) ([]byte, error) {
+	if len(iv) != aes.BlockSize {
+		return nil, errors.Wrapf(ErrInvalidLength, "iv must be %v bytes, got %v", aes.BlockSize, len(iv))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating aes cipher")
+	}
+	out := make([]byte, len(in))
+	cipher.NewCTR(block, iv).XORKeyStream(out, in)
+	return out, nil
+}
+
+// newUUID generates a random (version 4) UUID for the keystore's id field.
+func newUUID() (string, error) {
+	u := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, u); err != nil {
+		return "", err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
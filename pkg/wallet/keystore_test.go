@@ -0,0 +1,153 @@
+package wallet
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+)
+
+// These vectors are the official examples from the Web3 Secret Storage definition.
+const (
+	keystoreVectorPassphrase    = "testpassword"
+	keystoreVectorPrivateKeyHex = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+
+	keystoreVectorScrypt = `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "83dbcc02d8ccb40e466191a123791e0e"},
+			"ciphertext": "d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c",
+			"kdf": "scrypt",
+			"kdfparams": {"dklen": 32, "n": 262144, "r": 1, "p": 8, "salt": "ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},
+			"mac": "2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`
+
+	keystoreVectorPBKDF2 = `{
+		"crypto": {
+			"cipher": "aes-128-ctr",
+			"cipherparams": {"iv": "6087dab2f9fdbbfaddc31a909735c1e6"},
+			"ciphertext": "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46",
+			"kdf": "pbkdf2",
+			"kdfparams": {"c": 262144, "dklen": 32, "prf": "hmac-sha256", "salt": "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},
+			"mac": "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"
+		},
+		"id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+		"version": 3
+	}`
+)
+
+func Test_FromKeystore(t *testing.T) {
+	tests := []struct {
+		name     string
+		keystore string
+	}{
+		{name: "scrypt", keystore: keystoreVectorScrypt},
+		{name: "pbkdf2", keystore: keystoreVectorPBKDF2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, err := FromKeystore([]byte(test.keystore), keystoreVectorPassphrase)
+			require.NoError(t, err)
+
+			assert.Equal(t, keystoreVectorPrivateKeyHex, w.PrivateKeyHex())
+			assert.NoError(t, w.Validate())
+		})
+	}
+}
+
+func Test_FromKeystore_WrongPassphrase(t *testing.T) {
+	_, err := FromKeystore([]byte(keystoreVectorPBKDF2), "wrongpassword")
+
+	assert.Error(t, err)
+}
+
+func Test_FromKeystore_InvalidIV(t *testing.T) {
+	// The mac only covers the ciphertext, so a keystore with a mangled iv still passes it and reaches decryption.
+	keystore := strings.Replace(keystoreVectorPBKDF2, "6087dab2f9fdbbfaddc31a909735c1e6", "6087dab2", 1)
+
+	_, err := FromKeystore([]byte(keystore), keystoreVectorPassphrase)
+	assert.True(t, errors.Is(err, ErrInvalidLength), "expected ErrInvalidLength, got %v", err)
+}
+
+func Test_Wallet_EncryptKeystore(t *testing.T) {
+	tests := []struct {
+		name string
+		opts KeystoreOptions
+	}{
+		{name: "scrypt", opts: KeystoreOptions{KDF: KDFScrypt, ScryptN: 1 << 4, ScryptP: 1}},
+		{name: "pbkdf2", opts: KeystoreOptions{KDF: KDFPBKDF2, PBKDF2Iterations: 16}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := MustManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, wallettest.Address)
+
+			keystoreBytes, err := w.EncryptKeystore("passphrase", test.opts)
+			require.NoError(t, err)
+			assert.Contains(t, string(keystoreBytes), strings.ToLower(wallettest.Address[2:]))
+
+			w2, err := FromKeystore(keystoreBytes, "passphrase")
+			require.NoError(t, err)
+			assert.NoError(t, w.Equals(w2))
+
+			_, err = FromKeystore(keystoreBytes, "not the passphrase")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_KeystoreOptions_Memory(t *testing.T) {
+	assert.Equal(t, 256<<20, StandardKeystoreOptions.Memory())
+	assert.Equal(t, 4<<20, LightKeystoreOptions.Memory())
+	assert.Equal(t, 0, KeystoreOptions{KDF: KDFPBKDF2, PBKDF2Iterations: 16}.Memory())
+}
+
+func Test_KeystoreAddress(t *testing.T) {
+	w := MustManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, wallettest.Address)
+	keystoreBytes, err := w.EncryptKeystore("passphrase", KeystoreOptions{KDF: KDFScrypt, ScryptN: 1 << 4, ScryptP: 1})
+	require.NoError(t, err)
+
+	a, err := KeystoreAddress(keystoreBytes)
+	require.NoError(t, err)
+	assert.Equal(t, wallettest.Address, a.Hex())
+
+	// The official vectors do not record an address.
+	_, err = KeystoreAddress([]byte(keystoreVectorScrypt))
+	assert.Error(t, err)
+}
+
+func Test_Wallet_WriteKeystoreFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jeth-keystore")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	w := MustManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, wallettest.Address)
+
+	path, err := w.WriteKeystoreFile(dir, "passphrase", KeystoreOptions{KDF: KDFScrypt, ScryptN: 1 << 4, ScryptP: 1})
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(filepath.Base(path), "--"+strings.ToLower(wallettest.Address[2:])))
+
+	w2, err := FromKeystoreFile(path, "passphrase")
+	require.NoError(t, err)
+	assert.NoError(t, w.Equals(w2))
+}
+
+func Test_KeystoreFileName(t *testing.T) {
+	ts := time.Date(2021, 7, 17, 19, 19, 21, 123456789, time.UTC)
+
+	name := KeystoreFileName(wallettest.Address, ts)
+
+	assert.Equal(t, "UTC--2021-07-17T19-19-21.123456789Z--19325d2d5c17af1096d28a12850d27bd182612f6", name)
+}
@@ -0,0 +1,138 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SignatureLength is the length of a signature in bytes, r (32 bytes) || s (32 bytes) || v (1 byte).
+	SignatureLength = 65
+	// SignatureVOffset is added to the recovery id of personal message and typed data signatures, making v 27 or 28 the way MetaMask and other wallets produce it.
+	SignatureVOffset = 27
+)
+
+var (
+	// ErrInvalidSignature means a signature is not 65 bytes or its recovery id, v, is not 0, 1, 27, or 28.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrSignerMismatch means a signature was valid, but was not made by the expected address.
+	ErrSignerMismatch = errors.New("signature was not made by the expected address")
+)
+
+// MessageHash returns the EIP-191 (version 0x45) hash of message, keccak256("\x19Ethereum Signed Message:\n" + len(message) + message).
+// This prefix is what prevents a signed message from ever doubling as a signed transaction.
+func MessageHash(message []byte) []byte {
+	return accounts.TextHash(message)
+}
+
+// SignHash signs a 32 byte hash with w's private key and returns the signature in its 65 byte r || s || v form, where v is the raw recovery id, 0 or 1.
+// Never sign a hash you did not compute yourself, it could just as well be the hash of a transaction draining w. Prefer SignMessage.
+func (w *Wallet) SignHash(hash []byte) ([]byte, error) {
+	if w.IsWatchOnly() {
+		return nil, ErrWatchOnly
+	}
+	if len(hash) != common.HashLength {
+		return nil, fmt.Errorf("hash must be %v bytes, got %v", common.HashLength, len(hash))
+	}
+
+	signature, err := ethcrypto.Sign(hash, w.privateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "signing hash")
+	}
+
+	return signature, nil
+}
+
+// SignMessage signs the EIP-191 MessageHash of message with w's private key, as personal_sign does.
+// The signature is in its 65 byte r || s || v form, where v is 27 or 28, the same as MetaMask produces.
+func (w *Wallet) SignMessage(message []byte) ([]byte, error) {
+	signature, err := w.SignHash(MessageHash(message))
+	if err != nil {
+		return nil, errors.Wrap(err, "signing message hash")
+	}
+
+	signature[SignatureLength-1] += SignatureVOffset
+
+	return signature, nil
+}
+
+// VerifyMessage checks that signature is w's signature of message, see the package level VerifyMessage.
+func (w *Wallet) VerifyMessage(message, signature []byte) error {
+	return VerifyMessage(w.Address(), message, signature)
+}
+
+// VerifyMessage checks that signature is a signature of message made by address.
+// If no error is returned then the signature is valid, otherwise the error wraps ErrInvalidSignature or ErrSignerMismatch.
+func VerifyMessage(address string, message, signature []byte) error {
+	return VerifyHash(address, MessageHash(message), signature)
+}
+
+// VerifyHash checks that signature is a signature of the 32 byte hash made by address.
+// If no error is returned then the signature is valid, otherwise the error wraps ErrInvalidSignature or ErrSignerMismatch.
+func VerifyHash(address string, hash, signature []byte) error {
+	expected, err := ParseAddress(address)
+	if err != nil {
+		return errors.Wrap(err, "parsing address")
+	}
+
+	signer, err := RecoverHashSigner(hash, signature)
+	if err != nil {
+		return errors.Wrap(err, "recovering signer")
+	}
+
+	// Addresses are compared by value, not by their EIP-55 casing.
+	if MustParseAddress(signer) != expected {
+		return errors.Wrapf(ErrSignerMismatch, "expected: \"%s\", signer: \"%s\"", address, signer)
+	}
+
+	return nil
+}
+
+// RecoverMessageSigner returns the EIP-55 checksummed address which signed the EIP-191 MessageHash of message to produce signature.
+// Any valid signature recovers to some address, so the result must still be compared against the address you expected, VerifyMessage does this.
+func RecoverMessageSigner(message, signature []byte) (string, error) {
+	return RecoverHashSigner(MessageHash(message), signature)
+}
+
+// RecoverHashSigner returns the EIP-55 checksummed address which signed the 32 byte hash to produce signature.
+// signature must be in its 65 byte r || s || v form, v may be either 27 or 28 as SignMessage produces, or the raw recovery id 0 or 1 as SignHash and some hardware wallets produce.
+func RecoverHashSigner(hash, signature []byte) (string, error) {
+	w, err := FromSignature(hash, signature)
+	if err != nil {
+		return "", err
+	}
+
+	return w.Address(), nil
+}
+
+// FromSignature recovers the public key which signed the 32 byte hash to produce signature, returning a watch-only Wallet for it.
+// signature is in the same form RecoverHashSigner takes. Any valid signature recovers to some public key, so the result must still be compared against the signer you expected.
+func FromSignature(hash, signature []byte) (*Wallet, error) {
+	if len(hash) != common.HashLength {
+		return nil, fmt.Errorf("hash must be %v bytes, got %v", common.HashLength, len(hash))
+	}
+	if len(signature) != SignatureLength {
+		return nil, errors.Wrapf(ErrInvalidSignature, "signature must be %v bytes, got %v", SignatureLength, len(signature))
+	}
+
+	// Copy the signature so normalizing v does not modify the caller's slice.
+	sig := make([]byte, SignatureLength)
+	copy(sig, signature)
+	if sig[SignatureLength-1] >= SignatureVOffset {
+		sig[SignatureLength-1] -= SignatureVOffset
+	}
+	if sig[SignatureLength-1] > 1 {
+		return nil, errors.Wrapf(ErrInvalidSignature, "v must be 0, 1, 27, or 28, got %v", signature[SignatureLength-1])
+	}
+
+	publicKey, err := ethcrypto.SigToPub(hash, sig)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidSignature, err.Error())
+	}
+
+	return WatchOnlyFromPublicKey(publicKey)
+}
@@ -0,0 +1,122 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+)
+
+// This vector is the personal message signing example from the web3.js documentation, the same signature MetaMask produces for it.
+const (
+	messageVectorPrivateKeyHex = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	messageVectorAddress       = "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"
+	messageVectorMessage       = "Some data"
+	messageVectorSignatureHex  = "b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c"
+)
+
+func Test_MessageHash(t *testing.T) {
+	hash := MessageHash([]byte("hello"))
+
+	assert.Equal(t, "50b2c43fd39106bafbba0da34fc430e1f91e3c96ea2acee2bc34119f92b37750", hex.EncodeToString(hash))
+}
+
+func Test_Wallet_SignMessage(t *testing.T) {
+	w := MustFromPrivateKeyHex(messageVectorPrivateKeyHex)
+	require.Equal(t, messageVectorAddress, w.Address())
+
+	signature, err := w.SignMessage([]byte(messageVectorMessage))
+	require.NoError(t, err)
+
+	assert.Equal(t, messageVectorSignatureHex, hex.EncodeToString(signature))
+	assert.NoError(t, w.VerifyMessage([]byte(messageVectorMessage), signature))
+}
+
+func Test_Wallet_SignHash(t *testing.T) {
+	w := MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+
+	_, err := w.SignHash([]byte("not 32 bytes"))
+	assert.Error(t, err)
+
+	signature, err := w.SignHash(MessageHash([]byte(messageVectorMessage)))
+	require.NoError(t, err)
+	assert.Len(t, signature, SignatureLength)
+	assert.Contains(t, []byte{0, 1}, signature[SignatureLength-1])
+}
+
+func Test_RecoverMessageSigner(t *testing.T) {
+	signature, err := hex.DecodeString(messageVectorSignatureHex)
+	require.NoError(t, err)
+
+	signer, err := RecoverMessageSigner([]byte(messageVectorMessage), signature)
+	require.NoError(t, err)
+	assert.Equal(t, messageVectorAddress, signer)
+
+	// The raw recovery id is accepted as well as 27/28, and the caller's signature is left untouched.
+	raw := make([]byte, len(signature))
+	copy(raw, signature)
+	raw[SignatureLength-1] -= 27
+	signer, err = RecoverMessageSigner([]byte(messageVectorMessage), raw)
+	require.NoError(t, err)
+	assert.Equal(t, messageVectorAddress, signer)
+	assert.Equal(t, byte(1), raw[SignatureLength-1])
+}
+
+func Test_FromSignature(t *testing.T) {
+	w := MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+	hash := MessageHash([]byte(messageVectorMessage))
+	signature, err := w.SignHash(hash)
+	require.NoError(t, err)
+
+	recovered, err := FromSignature(hash, signature)
+	require.NoError(t, err)
+	assert.True(t, recovered.IsWatchOnly())
+	assert.Equal(t, wallettest.PublicKeyHex, recovered.PublicKeyHex())
+	assert.Equal(t, wallettest.Address, recovered.Address())
+
+	_, err = FromSignature(hash[:31], signature)
+	assert.Error(t, err)
+	_, err = FromSignature(hash, signature[:64])
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+}
+
+func Test_VerifyMessage(t *testing.T) {
+	signature, err := hex.DecodeString(messageVectorSignatureHex)
+	require.NoError(t, err)
+
+	badV := make([]byte, len(signature))
+	copy(badV, signature)
+	badV[SignatureLength-1] = 29
+
+	tests := []struct {
+		name      string
+		address   string
+		message   string
+		signature []byte
+		err       error
+	}{
+		{name: "valid", address: messageVectorAddress, message: messageVectorMessage, signature: signature},
+		{name: "valid lowercase address", address: "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23", message: messageVectorMessage, signature: signature},
+		{name: "wrong address", address: wallettest.Address, message: messageVectorMessage, signature: signature, err: ErrSignerMismatch},
+		{name: "wrong message", address: messageVectorAddress, message: "Some other data", signature: signature, err: ErrSignerMismatch},
+		{name: "short signature", address: messageVectorAddress, message: messageVectorMessage, signature: signature[:64], err: ErrInvalidSignature},
+		{name: "bad v", address: messageVectorAddress, message: messageVectorMessage, signature: badV, err: ErrInvalidSignature},
+		{name: "bad address", address: "0x2c75", message: messageVectorMessage, signature: signature, err: ErrInvalidLength},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := VerifyMessage(test.address, []byte(test.message), test.signature)
+
+			if test.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, test.err), "expected %v, got %v", test.err, err)
+		})
+	}
+}
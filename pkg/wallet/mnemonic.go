@@ -0,0 +1,158 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// mnemonicSeedIterations is the number of PBKDF2 rounds BIP-39 uses to stretch a mnemonic into a seed.
+	mnemonicSeedIterations = 2048
+	// mnemonicSeedLength is the length in bytes of a BIP-39 seed.
+	mnemonicSeedLength = 64
+)
+
+// englishWordIndex maps each word of englishWordlist to its index.
+var englishWordIndex = func() map[string]int {
+	index := make(map[string]int, len(englishWordlist))
+	for i, word := range englishWordlist {
+		index[word] = i
+	}
+	return index
+}()
+
+// NewMnemonic generates a random BIP-39 mnemonic of words words from the English wordlist.
+// words must be one of 12, 15, 18, 21, or 24, the more words the more entropy (128 bits for 12 words up to 256 bits for 24 words).
+func NewMnemonic(words int) (string, error) {
+	if words < 12 || words > 24 || words%3 != 0 {
+		return "", fmt.Errorf("mnemonic must be 12, 15, 18, 21, or 24 words, got %v", words)
+	}
+
+	// Every 3 words encode 32 bits of entropy plus 1 checksum bit.
+	entropy := make([]byte, words/3*4)
+	if _, err := io.ReadFull(rand.Reader, entropy); err != nil {
+		return "", errors.Wrap(err, "generating entropy")
+	}
+
+	return EntropyToMnemonic(entropy)
+}
+
+// EntropyToMnemonic encodes entropy as a BIP-39 mnemonic using the English wordlist.
+// entropy must be a multiple of 4 bytes between 16 and 32 bytes.
+func EntropyToMnemonic(entropy []byte) (string, error) {
+	if len(entropy) < 16 || len(entropy) > 32 || len(entropy)%4 != 0 {
+		return "", fmt.Errorf("entropy must be 16, 20, 24, 28, or 32 bytes, got %v", len(entropy))
+	}
+
+	// The checksum is the first len(entropy)/4 bits of the entropy's SHA256 hash, appended to the entropy.
+	checksumBits := uint(len(entropy) / 4)
+	hash := sha256.Sum256(entropy)
+	checksum := new(big.Int).SetBytes(hash[:1])
+	checksum.Rsh(checksum, 8-checksumBits)
+
+	b := new(big.Int).SetBytes(entropy)
+	b.Lsh(b, checksumBits)
+	b.Or(b, checksum)
+
+	// Each word then encodes the next 11 bits, most significant first.
+	numWords := (len(entropy)*8 + int(checksumBits)) / 11
+	words := make([]string, numWords)
+	mask := big.NewInt(2047)
+	for i := numWords - 1; i >= 0; i-- {
+		index := new(big.Int).And(b, mask)
+		words[i] = englishWordlist[index.Int64()]
+		b.Rsh(b, 11)
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy decodes mnemonic back into the entropy it encodes, verifying the mnemonic's checksum along the way.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, fmt.Errorf("mnemonic must be 12, 15, 18, 21, or 24 words, got %v", len(words))
+	}
+
+	b := new(big.Int)
+	for _, word := range words {
+		index, ok := englishWordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("\"%s\" is not a word in the BIP-39 English wordlist", word)
+		}
+		b.Lsh(b, 11)
+		b.Or(b, big.NewInt(int64(index)))
+	}
+
+	checksumBits := uint(len(words) / 3)
+	entropyLength := len(words) / 3 * 4
+
+	checksum := new(big.Int).And(b, big.NewInt(1<<checksumBits-1))
+	b.Rsh(b, checksumBits)
+	entropy := make([]byte, entropyLength)
+	b.FillBytes(entropy)
+
+	hash := sha256.Sum256(entropy)
+	expectedChecksum := new(big.Int).SetBytes(hash[:1])
+	expectedChecksum.Rsh(expectedChecksum, 8-checksumBits)
+	if checksum.Cmp(expectedChecksum) != 0 {
+		return nil, errors.New("mnemonic checksum is invalid, check the words are spelled correctly and in the right order")
+	}
+
+	return entropy, nil
+}
+
+// ValidateMnemonic checks that mnemonic is made up of English wordlist words and that its checksum is correct.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err
+}
+
+// MnemonicToSeed validates mnemonic and stretches it, along with the optional passphrase, into a 64 byte BIP-39 seed.
+// Note that every passphrase produces a valid (but different) seed, so a mistyped passphrase restores a different wallet rather than failing.
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, errors.Wrap(err, "validating mnemonic")
+	}
+
+	// BIP-39 specifies that both the mnemonic and passphrase are NFKD normalized, and that words are separated by a single space.
+	mnemonic = norm.NFKD.String(strings.Join(strings.Fields(mnemonic), " "))
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+
+	return pbkdf2.Key([]byte(mnemonic), []byte(salt), mnemonicSeedIterations, mnemonicSeedLength, sha512.New), nil
+}
+
+// FromMnemonic restores the Wallet at DefaultDerivationPath of mnemonic and passphrase, the same wallet MetaMask and most other wallets would restore.
+func FromMnemonic(mnemonic, passphrase string) (*Wallet, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "converting mnemonic to seed")
+	}
+
+	return FromSeed(seed, DefaultDerivationPath)
+}
+
+// NewWithMnemonic creates a new Wallet from a randomly generated mnemonic of words words, returning the mnemonic alongside it.
+// The mnemonic (and passphrase, if any) is all that is needed to restore the wallet via FromMnemonic.
+func NewWithMnemonic(words int, passphrase string) (*Wallet, string, error) {
+	mnemonic, err := NewMnemonic(words)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "generating mnemonic")
+	}
+
+	w, err := FromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "deriving wallet from mnemonic")
+	}
+
+	return w, mnemonic, nil
+}
@@ -0,0 +1,145 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bip39Vectors is the layout of testdata/bip39_vectors.json, the official BIP-39 English test vectors.
+// Each vector is [entropy, mnemonic, seed] where the seed is derived with passphrase.
+type bip39Vectors struct {
+	Passphrase string      `json:"passphrase"`
+	English    [][3]string `json:"english"`
+}
+
+func loadBIP39Vectors(t *testing.T) bip39Vectors {
+	raw, err := ioutil.ReadFile("testdata/bip39_vectors.json")
+	require.NoError(t, err)
+
+	var vectors bip39Vectors
+	require.NoError(t, json.Unmarshal(raw, &vectors))
+	require.NotEmpty(t, vectors.English)
+
+	return vectors
+}
+
+func Test_englishWordlist(t *testing.T) {
+	// The checksum of https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt
+	checksum := crc32.ChecksumIEEE([]byte(strings.Join(englishWordlist[:], "\n") + "\n"))
+
+	assert.Equal(t, "c1dbd296", fmt.Sprintf("%x", checksum))
+}
+
+func Test_EntropyToMnemonic(t *testing.T) {
+	vectors := loadBIP39Vectors(t)
+
+	for _, vector := range vectors.English {
+		t.Run(vector[0], func(t *testing.T) {
+			entropy, err := hex.DecodeString(vector[0])
+			require.NoError(t, err)
+
+			mnemonic, err := EntropyToMnemonic(entropy)
+			require.NoError(t, err)
+
+			assert.Equal(t, vector[1], mnemonic)
+		})
+	}
+}
+
+func Test_MnemonicToEntropy(t *testing.T) {
+	vectors := loadBIP39Vectors(t)
+
+	for _, vector := range vectors.English {
+		t.Run(vector[0], func(t *testing.T) {
+			entropy, err := MnemonicToEntropy(vector[1])
+			require.NoError(t, err)
+
+			assert.Equal(t, vector[0], hex.EncodeToString(entropy))
+		})
+	}
+}
+
+func Test_MnemonicToSeed(t *testing.T) {
+	vectors := loadBIP39Vectors(t)
+
+	for _, vector := range vectors.English {
+		t.Run(vector[0], func(t *testing.T) {
+			seed, err := MnemonicToSeed(vector[1], vectors.Passphrase)
+			require.NoError(t, err)
+
+			assert.Equal(t, vector[2], hex.EncodeToString(seed))
+		})
+	}
+}
+
+func Test_ValidateMnemonic(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		valid    bool
+	}{
+		{name: "valid", mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", valid: true},
+		{name: "extra whitespace", mnemonic: "  abandon abandon abandon abandon abandon abandon\tabandon abandon abandon abandon abandon about ", valid: true},
+		{name: "bad checksum", mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", valid: false},
+		{name: "unknown word", mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon jeth", valid: false},
+		{name: "too short", mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon about", valid: false},
+		{name: "not a multiple of 3", mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateMnemonic(test.mnemonic)
+
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func Test_NewMnemonic(t *testing.T) {
+	for _, words := range []int{12, 15, 18, 21, 24} {
+		t.Run(fmt.Sprint(words), func(t *testing.T) {
+			mnemonic, err := NewMnemonic(words)
+			require.NoError(t, err)
+
+			assert.Len(t, strings.Fields(mnemonic), words)
+			assert.NoError(t, ValidateMnemonic(mnemonic))
+		})
+	}
+
+	_, err := NewMnemonic(13)
+	assert.Error(t, err)
+}
+
+func Test_FromMnemonic(t *testing.T) {
+	// The well known first address of the all "abandon" mnemonic, as derived by MetaMask.
+	w, err := FromMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", w.Address())
+	assert.NoError(t, w.Validate())
+}
+
+func Test_NewWithMnemonic(t *testing.T) {
+	w, mnemonic, err := NewWithMnemonic(24, "passphrase")
+	require.NoError(t, err)
+
+	w2, err := FromMnemonic(mnemonic, "passphrase")
+	require.NoError(t, err)
+	assert.NoError(t, w.Equals(w2))
+
+	w3, err := FromMnemonic(mnemonic, "")
+	require.NoError(t, err)
+	assert.Error(t, w.Equals(w3))
+}
@@ -0,0 +1,114 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// presalePBKDF2Iterations is the number of PBKDF2 rounds pyethsaletool stretched presale passphrases with.
+	presalePBKDF2Iterations = 2000
+)
+
+type (
+	// presaleJSON is the wallet file pyethsaletool gave buyers in the 2014 Ether presale.
+	presaleJSON struct {
+		EncSeed string `json:"encseed"`
+		EthAddr string `json:"ethaddr"`
+		Email   string `json:"email"`
+		BtcAddr string `json:"btcaddr"`
+	}
+)
+
+// FromPresale decrypts presaleBytes, a 2014 Ether presale wallet JSON document, with passphrase and derives a new Wallet from the private key inside.
+// The derived address must match the address the document records, which is also how a wrong passphrase is detected.
+func FromPresale(presaleBytes []byte, passphrase string) (*Wallet, error) {
+	var ps presaleJSON
+	if err := json.Unmarshal(presaleBytes, &ps); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling presale json")
+	}
+
+	encSeed, err := hex.DecodeString(ps.EncSeed)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidHex, err.Error())
+	}
+	// The seed is encrypted with AES-128-CBC, prefixed by its IV, so there must be at least the IV and one block.
+	if len(encSeed) < 2*aes.BlockSize || len(encSeed)%aes.BlockSize != 0 {
+		return nil, errors.Wrapf(ErrInvalidLength, "encrypted seed must be a whole number of at least 2 %v byte blocks, got %v bytes", aes.BlockSize, len(encSeed))
+	}
+	iv, cipherText := encSeed[:aes.BlockSize], encSeed[aes.BlockSize:]
+
+	// pyethsaletool used the passphrase as its own salt.
+	derivedKey := pbkdf2.Key([]byte(passphrase), []byte(passphrase), presalePBKDF2Iterations, 16, sha256.New)
+	block, err := aes.NewCipher(derivedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating aes cipher")
+	}
+	plainText := make([]byte, len(cipherText))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plainText, cipherText)
+
+	seed, err := pkcs7Unpad(plainText)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt presale wallet with given passphrase")
+	}
+
+	// The private key is the Keccak-256 hash of the decrypted seed.
+	privateKey, err := ethcrypto.ToECDSA(ethcrypto.Keccak256(seed))
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidPrivateKey, err.Error())
+	}
+
+	w, err := FromPrivateKey(privateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "deriving wallet from decrypted private key")
+	}
+
+	if !strings.EqualFold(strings.TrimPrefix(ps.EthAddr, "0x"), strings.TrimPrefix(w.Address(), "0x")) {
+		w.Destroy()
+		return nil, errors.Wrapf(ErrAddressMismatch, "could not decrypt presale wallet with given passphrase, presale address \"%s\" does not match derived address \"%s\"", ps.EthAddr, w.Address())
+	}
+
+	return w, nil
+}
+
+// PresaleAddress returns the address recorded in presaleBytes, a 2014 Ether presale wallet JSON document, without decrypting it.
+func PresaleAddress(presaleBytes []byte) (Address, error) {
+	var ps presaleJSON
+	if err := json.Unmarshal(presaleBytes, &ps); err != nil {
+		return Address{}, errors.Wrap(err, "unmarshalling presale json")
+	}
+
+	address, err := ParseAddress("0x" + strings.TrimPrefix(ps.EthAddr, "0x"))
+	if err != nil {
+		return Address{}, errors.Wrap(err, "parsing presale address")
+	}
+
+	return address, nil
+}
+
+// pkcs7Unpad strips the PKCS #7 padding from in. A wrong key almost always leaves invalid padding behind.
+func pkcs7Unpad(in []byte) ([]byte, error) {
+	if len(in) == 0 {
+		return nil, errors.New("nothing to unpad")
+	}
+
+	padding := int(in[len(in)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(in) {
+		return nil, errors.New("invalid padding")
+	}
+	for _, b := range in[len(in)-padding:] {
+		if int(b) != padding {
+			return nil, errors.New("invalid padding")
+		}
+	}
+
+	return in[:len(in)-padding], nil
+}
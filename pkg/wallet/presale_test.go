@@ -0,0 +1,41 @@
+package wallet
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// presaleVectorPassphrase and presaleVectorAddress belong to testdata/presale.json, the presale wallet go-ethereum tests its importer with.
+	presaleVectorPassphrase = "foo"
+	presaleVectorAddress    = "0xd4584b5f6229b7be90727b0fc8c6b91bb427821f"
+)
+
+func Test_FromPresale(t *testing.T) {
+	presaleBytes, err := ioutil.ReadFile("testdata/presale.json")
+	require.NoError(t, err)
+
+	w, err := FromPresale(presaleBytes, presaleVectorPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, MustParseAddress(presaleVectorAddress), w.Addr())
+	assert.NoError(t, w.Validate())
+
+	_, err = FromPresale(presaleBytes, "wrong")
+	assert.Error(t, err)
+
+	_, err = FromPresale([]byte(`{"encseed": "00", "ethaddr": "d4584b5f6229b7be90727b0fc8c6b91bb427821f"}`), presaleVectorPassphrase)
+	assert.True(t, errors.Is(err, ErrInvalidLength))
+}
+
+func Test_PresaleAddress(t *testing.T) {
+	presaleBytes, err := ioutil.ReadFile("testdata/presale.json")
+	require.NoError(t, err)
+
+	a, err := PresaleAddress(presaleBytes)
+	require.NoError(t, err)
+	assert.Equal(t, MustParseAddress(presaleVectorAddress), a)
+}
@@ -0,0 +1,75 @@
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Insulince/jeth/pkg/eth"
+)
+
+// A Wallet's private key must never end up in a log or a JSON document by accident, so every way of printing or marshaling a Wallet redacts it with eth.ObfuscateKey.
+// These are value receivers so that a Wallet is redacted whether it is printed by value or through a pointer,
+// otherwise fmt would fall back to printing the unexported fields, private key included.
+
+// walletJSON is the redacted JSON representation of a Wallet.
+type walletJSON struct {
+	Address    string `json:"address"`
+	PublicKey  string `json:"publicKey,omitempty"`
+	PrivateKey string `json:"privateKey,omitempty"`
+	WatchOnly  bool   `json:"watchOnly"`
+}
+
+// String returns w's address and public key alongside its obfuscated private key.
+func (w Wallet) String() string {
+	privateKey := "none (watch-only)"
+	if !w.IsWatchOnly() {
+		privateKey = eth.ObfuscateKey(w.PrivateKeyHex())
+	}
+	publicKey := "unknown"
+	if w.HasPublicKey() {
+		publicKey = w.PublicKeyHex()
+	}
+
+	return fmt.Sprintf("Wallet{address: %s, publicKey: %s, privateKey: %s}", w.Address(), publicKey, privateKey)
+}
+
+// Format implements fmt.Formatter so every verb, %v, %+v, %#v, %s, %x, and so on, prints the redacted String of w.
+func (w Wallet) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		_, _ = fmt.Fprintf(f, "%q", w.String())
+		return
+	}
+	_, _ = io.WriteString(f, w.String())
+}
+
+// MarshalJSON marshals w with its private key obfuscated, so the result identifies w without being able to restore it.
+// To export a Wallet that can be restored, encrypt it with EncryptKeystore.
+func (w Wallet) MarshalJSON() ([]byte, error) {
+	j := walletJSON{
+		Address:   w.Address(),
+		PublicKey: w.PublicKeyHex(),
+		WatchOnly: w.IsWatchOnly(),
+	}
+	if !w.IsWatchOnly() {
+		j.PrivateKey = eth.ObfuscateKey(w.PrivateKeyHex())
+	}
+
+	return json.Marshal(j)
+}
+
+// Destroy overwrites w's private key in memory and forgets it, leaving w watch-only. Call it as soon as w has nothing left to sign.
+// Go gives no guarantee that no other copy of the key exists, the garbage collector may have moved it and any string returned by PrivateKeyHex cannot be wiped,
+// so this narrows the window in which the key can be read out of memory rather than closing it.
+func (w *Wallet) Destroy() {
+	if w.IsWatchOnly() {
+		return
+	}
+
+	d := w.privateKey.D.Bits()
+	for i := range d {
+		d[i] = 0
+	}
+	w.privateKey.D.SetInt64(0)
+	w.privateKey = nil
+}
@@ -0,0 +1,90 @@
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+)
+
+const (
+	obfuscatedPrivateKeyHex = "************************************************************03ed"
+)
+
+func Test_Wallet_String(t *testing.T) {
+	w := MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+
+	assert.Equal(t, "Wallet{address: "+wallettest.Address+", publicKey: "+wallettest.PublicKeyHex+", privateKey: "+obfuscatedPrivateKeyHex+"}", w.String())
+	assert.Equal(t, "Wallet{address: "+wallettest.Address+", publicKey: unknown, privateKey: none (watch-only)}", WatchOnly(MustParseAddress(wallettest.Address)).String())
+}
+
+func Test_Wallet_Format(t *testing.T) {
+	w := MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+	holder := struct {
+		W  *Wallet
+		W2 Wallet
+	}{W: w, W2: *w}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%d"} {
+		t.Run(format, func(t *testing.T) {
+			for _, v := range []interface{}{w, *w, holder, []*Wallet{w}} {
+				s := fmt.Sprintf(format, v)
+				assert.NotContains(t, s, wallettest.PrivateKeyHex)
+				assert.Contains(t, s, obfuscatedPrivateKeyHex)
+			}
+		})
+	}
+}
+
+func Test_Wallet_MarshalJSON(t *testing.T) {
+	w := MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+
+	b, err := json.Marshal(w)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"address": "`+wallettest.Address+`", "publicKey": "`+wallettest.PublicKeyHex+`", "privateKey": "`+obfuscatedPrivateKeyHex+`", "watchOnly": false}`, string(b))
+
+	b, err = json.Marshal(struct{ W Wallet }{W: *w})
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), wallettest.PrivateKeyHex)
+
+	b, err = json.Marshal(WatchOnly(MustParseAddress(wallettest.Address)))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"address": "`+wallettest.Address+`", "watchOnly": true}`, string(b))
+}
+
+func Test_Wallet_Destroy(t *testing.T) {
+	w := MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+	privateKey := w.privateKey
+
+	w.Destroy()
+
+	assert.Equal(t, 0, privateKey.D.Sign())
+	assert.True(t, w.IsWatchOnly())
+	assert.Equal(t, "", w.PrivateKeyHex())
+	assert.Equal(t, wallettest.PublicKeyHex, w.PublicKeyHex())
+	assert.Equal(t, wallettest.Address, w.Address())
+	assert.NoError(t, w.Validate())
+
+	_, err := w.SignMessage([]byte("jeth"))
+	assert.True(t, errors.Is(err, ErrWatchOnly))
+
+	// Destroying twice is harmless.
+	w.Destroy()
+}
+
+func Test_Wallet_Equals_Redacted(t *testing.T) {
+	w := MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+	w2 := MustNew()
+
+	err := w.Equals(w2)
+
+	require.True(t, errors.Is(err, ErrPrivateKeyMismatch))
+	assert.NotContains(t, err.Error(), wallettest.PrivateKeyHex)
+	assert.NotContains(t, err.Error(), w2.PrivateKeyHex())
+	assert.Contains(t, err.Error(), obfuscatedPrivateKeyHex)
+}
@@ -0,0 +1,129 @@
+package wallet
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrChainIDMismatch means a signed transaction is not for the expected chain, or is a legacy transaction without EIP-155 replay protection which is valid on every chain.
+	ErrChainIDMismatch = errors.New("transaction is not for the expected chain")
+)
+
+// SignTx signs tx for the chain identified by chainID with w's private key, returning the signed copy of tx.
+// Every transaction type the chain supports can be signed, legacy transactions are signed with EIP-155 replay protection.
+func (w *Wallet) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if w.IsWatchOnly() {
+		return nil, ErrWatchOnly
+	}
+	if chainID == nil || chainID.Sign() <= 0 {
+		return nil, errors.New("chain id must be positive")
+	}
+
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), w.privateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "signing transaction")
+	}
+
+	return signedTx, nil
+}
+
+// ParseTransaction parses encoded, a transaction either as the JSON geth's types.Transaction marshals to (which send prints),
+// or as its canonical binary encoding (RLP for legacy transactions, the EIP-2718 type byte followed by RLP for typed transactions), raw or "0x" prefixed hexadecimal.
+func ParseTransaction(encoded []byte) (*types.Transaction, error) {
+	encoded = bytes.TrimSpace(encoded)
+
+	tx := new(types.Transaction)
+	switch {
+	case bytes.HasPrefix(encoded, []byte("{")):
+		if err := tx.UnmarshalJSON(encoded); err != nil {
+			return nil, errors.Wrap(err, "unmarshalling transaction json")
+		}
+	case bytes.HasPrefix(encoded, []byte("0x")):
+		b, err := hexutil.Decode(string(encoded))
+		if err != nil {
+			return nil, errors.Wrap(err, "decoding transaction hex")
+		}
+		if err := tx.UnmarshalBinary(b); err != nil {
+			return nil, errors.Wrap(err, "decoding transaction")
+		}
+	default:
+		if err := tx.UnmarshalBinary(encoded); err != nil {
+			return nil, errors.Wrap(err, "decoding transaction")
+		}
+	}
+
+	return tx, nil
+}
+
+// FromSignedTransaction parses encoded, see ParseTransaction, and recovers its sender, see FromTransaction. The parsed transaction is returned alongside the sender's watch-only Wallet.
+func FromSignedTransaction(encoded []byte, chainID *big.Int) (*Wallet, *types.Transaction, error) {
+	tx, err := ParseTransaction(encoded)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "parsing transaction")
+	}
+
+	w, err := FromTransaction(tx, chainID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return w, tx, nil
+}
+
+// FromTransaction recovers the public key which signed tx, returning a watch-only Wallet for its sender.
+// Legacy transactions, with or without EIP-155 replay protection, and typed transactions (EIP-2930 access list and EIP-1559 dynamic fee) are supported.
+// If chainID is not nil, tx must be for that chain, otherwise the returned error wraps ErrChainIDMismatch. A legacy transaction without replay protection is for no chain in particular, so it only passes a nil chainID.
+func FromTransaction(tx *types.Transaction, chainID *big.Int) (*Wallet, error) {
+	txChainID := tx.ChainId()
+	if !tx.Protected() {
+		txChainID = nil
+	}
+	if chainID != nil && (txChainID == nil || txChainID.Cmp(chainID) != 0) {
+		if txChainID == nil {
+			return nil, errors.Wrapf(ErrChainIDMismatch, "expected chain id %s, but the transaction has no EIP-155 replay protection and is valid on every chain", chainID)
+		}
+		return nil, errors.Wrapf(ErrChainIDMismatch, "expected chain id %s, got %s", chainID, txChainID)
+	}
+
+	// types.Sender validates the signature values as consensus does, such as rejecting the malleable upper half of s.
+	signer := types.LatestSignerForChainID(txChainID)
+	sender, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidSignature, err.Error())
+	}
+
+	// Turn v back into the raw recovery id, undoing the offset each transaction type adds to it.
+	v, r, s := tx.RawSignatureValues()
+	recoveryID := new(big.Int).Set(v)
+	switch {
+	case tx.Type() != types.LegacyTxType:
+	case txChainID != nil:
+		recoveryID.Sub(recoveryID, new(big.Int).Add(big.NewInt(35), new(big.Int).Mul(txChainID, big.NewInt(2))))
+	default:
+		recoveryID.Sub(recoveryID, big.NewInt(SignatureVOffset))
+	}
+	if !recoveryID.IsUint64() || recoveryID.Uint64() > 1 {
+		return nil, errors.Wrapf(ErrInvalidSignature, "v %s does not hold a recovery id", v)
+	}
+
+	signature := make([]byte, SignatureLength)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:64])
+	signature[SignatureLength-1] = byte(recoveryID.Uint64())
+
+	hash := signer.Hash(tx)
+	w, err := FromSignature(hash[:], signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "recovering public key")
+	}
+	if w.Addr() != Address(sender) {
+		return nil, errors.Wrapf(ErrAddressMismatch, "recovered %s but the transaction was sent by %s", w.Address(), sender.Hex())
+	}
+
+	return w, nil
+}
@@ -0,0 +1,93 @@
+package wallet
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
+)
+
+// This vector is the signed example transaction from EIP-155, its private key is 0x4646...46.
+const (
+	eip155VectorTxHex   = "0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+	eip155VectorAddress = "0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F"
+)
+
+func Test_FromSignedTransaction_EIP155Vector(t *testing.T) {
+	w, tx, err := FromSignedTransaction([]byte(eip155VectorTxHex), big.NewInt(1))
+	require.NoError(t, err)
+
+	assert.True(t, w.IsWatchOnly())
+	assert.Equal(t, eip155VectorAddress, w.Address())
+	assert.Equal(t, uint64(9), tx.Nonce())
+
+	_, _, err = FromSignedTransaction([]byte(eip155VectorTxHex), big.NewInt(5))
+	assert.True(t, errors.Is(err, ErrChainIDMismatch), "expected %v, got %v", ErrChainIDMismatch, err)
+}
+
+func Test_FromSignedTransaction(t *testing.T) {
+	w := MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+	to := common.HexToAddress(eip155VectorAddress)
+	chainID := big.NewInt(1)
+
+	tests := []struct {
+		name string
+		tx   types.TxData
+	}{
+		{name: "legacy", tx: &types.LegacyTx{Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(1)}},
+		{name: "access list", tx: &types.AccessListTx{ChainID: chainID, Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(1)}},
+		{name: "dynamic fee", tx: &types.DynamicFeeTx{ChainID: chainID, Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 21000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			signedTx, err := w.SignTx(types.NewTx(test.tx), chainID)
+			require.NoError(t, err)
+
+			jsonBytes, err := signedTx.MarshalJSON()
+			require.NoError(t, err)
+			binaryBytes, err := signedTx.MarshalBinary()
+			require.NoError(t, err)
+
+			for _, encoded := range [][]byte{jsonBytes, binaryBytes} {
+				recovered, tx, err := FromSignedTransaction(encoded, chainID)
+				require.NoError(t, err)
+				assert.Equal(t, wallettest.PublicKeyHex, recovered.PublicKeyHex())
+				assert.Equal(t, wallettest.Address, recovered.Address())
+				assert.Equal(t, signedTx.Hash(), tx.Hash())
+			}
+
+			_, _, err = FromSignedTransaction(binaryBytes, big.NewInt(3))
+			assert.True(t, errors.Is(err, ErrChainIDMismatch), "expected %v, got %v", ErrChainIDMismatch, err)
+		})
+	}
+}
+
+func Test_FromTransaction_Unprotected(t *testing.T) {
+	w := MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+	to := common.HexToAddress(eip155VectorAddress)
+
+	tx, err := types.SignTx(types.NewTx(&types.LegacyTx{Nonce: 1, To: &to, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(1)}), types.HomesteadSigner{}, w.privateKey)
+	require.NoError(t, err)
+
+	_, err = FromTransaction(tx, big.NewInt(1))
+	assert.True(t, errors.Is(err, ErrChainIDMismatch), "expected %v, got %v", ErrChainIDMismatch, err)
+
+	recovered, err := FromTransaction(tx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, wallettest.Address, recovered.Address())
+}
+
+func Test_FromTransaction_Unsigned(t *testing.T) {
+	to := common.HexToAddress(eip155VectorAddress)
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(1), To: &to, Value: big.NewInt(1), Gas: 21000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2)})
+
+	_, err := FromTransaction(tx, big.NewInt(1))
+	assert.True(t, errors.Is(err, ErrInvalidSignature), "expected %v, got %v", ErrInvalidSignature, err)
+}
@@ -2,8 +2,11 @@ package wallet
 
 import (
 	"crypto/ecdsa"
+	"encoding/hex"
 	"fmt"
 
+	"github.com/Insulince/jeth/pkg/eth"
+
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
@@ -14,6 +17,7 @@ import (
 // 	2. The public key, derived from the private key.
 // 	3. The address, derived from the public key.
 // To access these fields from another package in hexadecimal format, use the receiver functions PrivateKeyHex, PublicKeyHex, and Address.
+// A watch-only wallet (see WatchOnly and WatchOnlyFromPublicKey) has no private key, and possibly no public key, it can be watched but not signed with.
 type Wallet struct {
 	privateKey *ecdsa.PrivateKey
 	publicKey  *ecdsa.PublicKey
@@ -21,18 +25,27 @@ type Wallet struct {
 }
 
 // New creates a new Wallet from a randomly generated private key.
-func New() *Wallet {
+func New() (*Wallet, error) {
 	// PRIVATE KEY GENERATION
 	// Generate new private key.
 	privateKey, err := ethcrypto.GenerateKey()
 	if err != nil {
-		panic(errors.Wrap(err, "generating private key"))
+		return nil, errors.Wrap(err, "generating private key")
 	}
 
 	// Derive the rest of the wallet (public key + wallet) from this private key.
 	return FromPrivateKey(privateKey)
 }
 
+// MustNew calls New and panics if it returns an error.
+func MustNew() *Wallet {
+	w, err := New()
+	if err != nil {
+		panic(errors.Wrap(err, "must new"))
+	}
+	return w
+}
+
 // Manual creates a new Wallet with privateKey, publicKey, and address.
 // It would be wise to Wallet.Validate this wallet after creating it.
 func Manual(privateKey *ecdsa.PrivateKey, publicKey *ecdsa.PublicKey, address string) *Wallet {
@@ -46,39 +59,90 @@ func Manual(privateKey *ecdsa.PrivateKey, publicKey *ecdsa.PublicKey, address st
 }
 
 // ManualHex creates a new Wallet with privateKeyHex and publicKeyHex parsed into ECDSA format and address as-is.
-func ManualHex(privateKeyHex, publicKeyHex, address string) *Wallet {
-	privateKey := PrivateKeyHexToECDSA(privateKeyHex)
-	publicKey := PublicKeyHexToECDSA(publicKeyHex)
+// address is only checked to be well-formed, Wallet.Validate checks that it actually belongs to the keys.
+func ManualHex(privateKeyHex, publicKeyHex, address string) (*Wallet, error) {
+	privateKey, err := PrivateKeyHexToECDSA(privateKeyHex)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing private key")
+	}
+	publicKey, err := PublicKeyHexToECDSA(publicKeyHex)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing public key")
+	}
+	if _, err := ParseAddress(address); err != nil {
+		return nil, errors.Wrap(err, "parsing address")
+	}
 
-	return Manual(privateKey, publicKey, address)
+	return Manual(privateKey, publicKey, address), nil
+}
+
+// MustManualHex calls ManualHex and panics if it returns an error.
+func MustManualHex(privateKeyHex, publicKeyHex, address string) *Wallet {
+	w, err := ManualHex(privateKeyHex, publicKeyHex, address)
+	if err != nil {
+		panic(errors.Wrap(err, "must manual hex"))
+	}
+	return w
 }
 
 // FromPrivateKey derives a new Wallet from privateKey by deriving its public key.
 // This should be called from New when a new wallet is being created from scratch.
-func FromPrivateKey(privateKey *ecdsa.PrivateKey) *Wallet {
+func FromPrivateKey(privateKey *ecdsa.PrivateKey) (*Wallet, error) {
+	if privateKey == nil || privateKey.D == nil {
+		return nil, errors.Wrap(ErrInvalidPrivateKey, "private key is nil")
+	}
+
 	// PUBLIC KEY DERIVATION
 	// Derive public key from private key.
 	publicKeyCrypto := privateKey.Public()
 	// Cast public key to *ecdsa.PublicKey type.
 	publicKey, ok := publicKeyCrypto.(*ecdsa.PublicKey)
 	if !ok {
-		panic(fmt.Errorf("cannot case public key to ECDSA, is of type \"%T\"\n", publicKeyCrypto))
+		return nil, fmt.Errorf("cannot cast public key to ECDSA, is of type \"%T\"", publicKeyCrypto)
 	}
 
 	// Derive the rest of the wallet (the address) from the given private key and this public key.
 	return FromPublicKey(privateKey, publicKey)
 }
 
+// MustFromPrivateKey calls FromPrivateKey and panics if it returns an error.
+func MustFromPrivateKey(privateKey *ecdsa.PrivateKey) *Wallet {
+	w, err := FromPrivateKey(privateKey)
+	if err != nil {
+		panic(errors.Wrap(err, "must from private key"))
+	}
+	return w
+}
+
 // FromPrivateKeyHex calls FromPrivateKey after parsing privateKeyHex into ECDSA format.
-func FromPrivateKeyHex(privateKeyHex string) *Wallet {
-	privateKey := PrivateKeyHexToECDSA(privateKeyHex)
+func FromPrivateKeyHex(privateKeyHex string) (*Wallet, error) {
+	privateKey, err := PrivateKeyHexToECDSA(privateKeyHex)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing private key")
+	}
 
 	return FromPrivateKey(privateKey)
 }
 
+// MustFromPrivateKeyHex calls FromPrivateKeyHex and panics if it returns an error.
+func MustFromPrivateKeyHex(privateKeyHex string) *Wallet {
+	w, err := FromPrivateKeyHex(privateKeyHex)
+	if err != nil {
+		panic(errors.Wrap(err, "must from private key hex"))
+	}
+	return w
+}
+
 // FromPublicKey derives a new Wallet from privateKey and publicKey by deriving its address.
 // This should be called from FromPrivateKey when a new wallet is being created from scratch.
-func FromPublicKey(privateKey *ecdsa.PrivateKey, publicKey *ecdsa.PublicKey) *Wallet {
+func FromPublicKey(privateKey *ecdsa.PrivateKey, publicKey *ecdsa.PublicKey) (*Wallet, error) {
+	if publicKey == nil || publicKey.X == nil || publicKey.Y == nil {
+		return nil, errors.Wrap(ErrOffCurve, "public key is nil")
+	}
+	if !ethcrypto.S256().IsOnCurve(publicKey.X, publicKey.Y) {
+		return nil, ErrOffCurve
+	}
+
 	// ADDRESS DERIVATION
 	// Simply use go-ethereum's crypto package's PubkeyToAddress function to derive the wallet address from the public key.
 	address := ethcrypto.PubkeyToAddress(*publicKey).Hex()
@@ -89,19 +153,99 @@ func FromPublicKey(privateKey *ecdsa.PrivateKey, publicKey *ecdsa.PublicKey) *Wa
 		address:    address,
 	}
 
+	return w, nil
+}
+
+// MustFromPublicKey calls FromPublicKey and panics if it returns an error.
+func MustFromPublicKey(privateKey *ecdsa.PrivateKey, publicKey *ecdsa.PublicKey) *Wallet {
+	w, err := FromPublicKey(privateKey, publicKey)
+	if err != nil {
+		panic(errors.Wrap(err, "must from public key"))
+	}
 	return w
 }
 
 // FromPublicKeyHex calls FromPublicKey after parsing privateKeyHex and publicKeyHex into ECDSA format.
-func FromPublicKeyHex(privateKeyHex, publicKeyHex string) *Wallet {
-	privateKey := PrivateKeyHexToECDSA(privateKeyHex)
-	publicKey := PublicKeyHexToECDSA(publicKeyHex)
+func FromPublicKeyHex(privateKeyHex, publicKeyHex string) (*Wallet, error) {
+	privateKey, err := PrivateKeyHexToECDSA(privateKeyHex)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing private key")
+	}
+	publicKey, err := PublicKeyHexToECDSA(publicKeyHex)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing public key")
+	}
 
 	return FromPublicKey(privateKey, publicKey)
 }
 
-// PrivateKeyHex returns w's privateKey in hexadecimal format.
+// MustFromPublicKeyHex calls FromPublicKeyHex and panics if it returns an error.
+func MustFromPublicKeyHex(privateKeyHex, publicKeyHex string) *Wallet {
+	w, err := FromPublicKeyHex(privateKeyHex, publicKeyHex)
+	if err != nil {
+		panic(errors.Wrap(err, "must from public key hex"))
+	}
+	return w
+}
+
+// WatchOnly creates a new watch-only Wallet which knows only its address.
+func WatchOnly(address Address) *Wallet {
+	w := &Wallet{
+		address: address.Hex(),
+	}
+
+	return w
+}
+
+// WatchOnlyFromPublicKey creates a new watch-only Wallet from publicKey by deriving its address.
+func WatchOnlyFromPublicKey(publicKey *ecdsa.PublicKey) (*Wallet, error) {
+	return FromPublicKey(nil, publicKey)
+}
+
+// MustWatchOnlyFromPublicKey calls WatchOnlyFromPublicKey and panics if it returns an error.
+func MustWatchOnlyFromPublicKey(publicKey *ecdsa.PublicKey) *Wallet {
+	w, err := WatchOnlyFromPublicKey(publicKey)
+	if err != nil {
+		panic(errors.Wrap(err, "must watch only from public key"))
+	}
+	return w
+}
+
+// WatchOnlyFromPublicKeyHex calls WatchOnlyFromPublicKey after parsing publicKeyHex into ECDSA format.
+func WatchOnlyFromPublicKeyHex(publicKeyHex string) (*Wallet, error) {
+	publicKey, err := PublicKeyHexToECDSA(publicKeyHex)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing public key")
+	}
+
+	return WatchOnlyFromPublicKey(publicKey)
+}
+
+// MustWatchOnlyFromPublicKeyHex calls WatchOnlyFromPublicKeyHex and panics if it returns an error.
+func MustWatchOnlyFromPublicKeyHex(publicKeyHex string) *Wallet {
+	w, err := WatchOnlyFromPublicKeyHex(publicKeyHex)
+	if err != nil {
+		panic(errors.Wrap(err, "must watch only from public key hex"))
+	}
+	return w
+}
+
+// IsWatchOnly reports whether w has no private key. Watch-only wallets return ErrWatchOnly from anything which needs the private key, such as signing.
+func (w *Wallet) IsWatchOnly() bool {
+	return w.privateKey == nil
+}
+
+// HasPublicKey reports whether w knows its public key. Only watch-only wallets created from just an address do not.
+func (w *Wallet) HasPublicKey() bool {
+	return w.publicKey != nil
+}
+
+// PrivateKeyHex returns w's privateKey in hexadecimal format, or "" if w is watch-only.
 func (w *Wallet) PrivateKeyHex() string {
+	if w.IsWatchOnly() {
+		return ""
+	}
+
 	// Dump private key to bytes.
 	privateKeyBytes := ethcrypto.FromECDSA(w.privateKey)
 	// Encode private key bytes into hexadecimal.
@@ -112,8 +256,12 @@ func (w *Wallet) PrivateKeyHex() string {
 	return privateKeyHex
 }
 
-// PublicKeyHex returns w's publicKey in hexadecimal format.
+// PublicKeyHex returns w's publicKey in hexadecimal format, or "" if w does not know its public key.
 func (w *Wallet) PublicKeyHex() string {
+	if !w.HasPublicKey() {
+		return ""
+	}
+
 	// Dump public key to bytes.
 	publicKeyBytes := ethcrypto.FromECDSAPub(w.publicKey)
 	// Encode public key bytes into hexadecimal.
@@ -130,26 +278,48 @@ func (w *Wallet) Address() string {
 	return w.address
 }
 
+// Addr returns w's address as an Address. Use Validate to be certain it is well-formed, an address which is not is returned as the zero address.
+func (w *Wallet) Addr() Address {
+	a, err := ParseAddress(w.address)
+	if err != nil {
+		return Address{}
+	}
+	return a
+}
+
 // Clone duplicates w into a new Wallet.
 func (w *Wallet) Clone() *Wallet {
-	w2 := ManualHex(w.PrivateKeyHex(), w.PublicKeyHex(), w.Address())
+	if w.IsWatchOnly() {
+		w2 := &Wallet{
+			address: w.address,
+		}
+		if w.HasPublicKey() {
+			// w's own public key hex encoding always parses, so this cannot panic.
+			w2.publicKey = MustPublicKeyHexToECDSA(w.PublicKeyHex())
+		}
+		return w2
+	}
+
+	// w's own hex encodings always parse, so this cannot panic.
+	w2 := MustManualHex(w.PrivateKeyHex(), w.PublicKeyHex(), w.Address())
 
 	return w2
 }
 
 // Equals checks if w contains the same wallet data as w2 by comparing their hexadecimal encodings.
-// If no error is returned then w and w2 are equal.
+// If no error is returned then w and w2 are equal, otherwise the error is one of ErrPrivateKeyMismatch, ErrPublicKeyMismatch, or ErrAddressMismatch.
 func (w *Wallet) Equals(w2 *Wallet) error {
 	if w.PrivateKeyHex() != w2.PrivateKeyHex() {
-		return fmt.Errorf("private keys are not equal: original: \"%s\", comparable: \"%s\"", w.PrivateKeyHex(), w2.PublicKeyHex())
+		// Never put a private key in an error, errors end up in logs.
+		return errors.Wrapf(ErrPrivateKeyMismatch, "original: \"%s\", comparable: \"%s\"", eth.ObfuscateKey(w.PrivateKeyHex()), eth.ObfuscateKey(w2.PrivateKeyHex()))
 	}
 
 	if w.PublicKeyHex() != w2.PublicKeyHex() {
-		return fmt.Errorf("public keys are not equal: original: \"%s\", comparable: \"%s\"", w.PublicKeyHex(), w2.PublicKeyHex())
+		return errors.Wrapf(ErrPublicKeyMismatch, "original: \"%s\", comparable: \"%s\"", w.PublicKeyHex(), w2.PublicKeyHex())
 	}
 
 	if w.Address() != w2.Address() {
-		return fmt.Errorf("addresses are not equal: original: \"%s\", comparable: \"%s\"", w.Address(), w2.Address())
+		return errors.Wrapf(ErrAddressMismatch, "original: \"%s\", comparable: \"%s\"", w.Address(), w2.Address())
 	}
 
 	return nil
@@ -159,9 +329,17 @@ func (w *Wallet) Equals(w2 *Wallet) error {
 // This is valuable because take, for example, if you had a wallet with random hexadecimal information it would look the same as a "true" wallet, but if the address
 // does not match the private key then you do not have access to the wallet.
 // Make HEAVY USE of this function to ensure your wallet is accurate and you still have access to it.
+// A watch-only wallet has no private key to derive from, so its address is checked against its public key if it has one, and only for being well-formed otherwise.
 func (w *Wallet) Validate() error {
+	if w.IsWatchOnly() {
+		return w.validateWatchOnly()
+	}
+
 	// Derive a new wallet from w.privateKey
-	w2 := FromPrivateKey(w.privateKey)
+	w2, err := FromPrivateKey(w.privateKey)
+	if err != nil {
+		return errors.Wrap(err, "deriving wallet from private key")
+	}
 
 	// Check if the derived wallet, w2, is equal to the original wallet, w.
 	if err := w.Equals(w2); err != nil {
@@ -173,27 +351,83 @@ func (w *Wallet) Validate() error {
 	return nil
 }
 
+// validateWatchOnly is Validate for watch-only wallets.
+func (w *Wallet) validateWatchOnly() error {
+	address, err := ParseAddress(w.address)
+	if err != nil {
+		return errors.Wrap(err, "parsing address")
+	}
+
+	if !w.HasPublicKey() {
+		return nil
+	}
+
+	w2, err := WatchOnlyFromPublicKey(w.publicKey)
+	if err != nil {
+		return errors.Wrap(err, "deriving wallet from public key")
+	}
+	if w2.Addr() != address {
+		return errors.Wrapf(ErrAddressMismatch, "original: \"%s\", derived from public key: \"%s\"", w.address, w2.Address())
+	}
+
+	return nil
+}
+
 // PrivateKeyHexToECDSA is a helper function for converting a hexadecimal representation of a private key into ECDSA format.
-func PrivateKeyHexToECDSA(privateKeyHex string) *ecdsa.PrivateKey {
-	privateKey, err := ethcrypto.HexToECDSA(privateKeyHex)
+// The returned error wraps ErrInvalidLength, ErrInvalidHex, or ErrInvalidPrivateKey depending on what is wrong with privateKeyHex.
+func PrivateKeyHexToECDSA(privateKeyHex string) (*ecdsa.PrivateKey, error) {
+	if len(privateKeyHex) != privateKeyHexLength {
+		return nil, errors.Wrapf(ErrInvalidLength, "private key must be %v hexadecimal characters, got %v", privateKeyHexLength, len(privateKeyHex))
+	}
+
+	privateKeyBytes, err := hex.DecodeString(privateKeyHex)
 	if err != nil {
-		panic(errors.Wrap(err, "converting private key hex to ecdsa"))
+		return nil, errors.Wrap(ErrInvalidHex, err.Error())
 	}
 
+	privateKey, err := ethcrypto.ToECDSA(privateKeyBytes)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidPrivateKey, err.Error())
+	}
+
+	return privateKey, nil
+}
+
+// MustPrivateKeyHexToECDSA calls PrivateKeyHexToECDSA and panics if it returns an error.
+func MustPrivateKeyHexToECDSA(privateKeyHex string) *ecdsa.PrivateKey {
+	privateKey, err := PrivateKeyHexToECDSA(privateKeyHex)
+	if err != nil {
+		panic(errors.Wrap(err, "converting private key hex to ecdsa"))
+	}
 	return privateKey
 }
 
 // PublicKeyHexToECDSA is a helper function for converting a hexadecimal representation of a public key into ECDSA format.
-func PublicKeyHexToECDSA(publicKeyHex string) *ecdsa.PublicKey {
-	publicKeyBytes, err := hexutil.Decode("0x04" + publicKeyHex)
+// The returned error wraps ErrInvalidLength, ErrInvalidHex, or ErrOffCurve depending on what is wrong with publicKeyHex.
+func PublicKeyHexToECDSA(publicKeyHex string) (*ecdsa.PublicKey, error) {
+	if len(publicKeyHex) != publicKeyHexLength {
+		return nil, errors.Wrapf(ErrInvalidLength, "public key must be %v hexadecimal characters, got %v", publicKeyHexLength, len(publicKeyHex))
+	}
+
+	publicKeyBytes, err := hex.DecodeString(publicKeyHex)
 	if err != nil {
-		panic(errors.Wrap(err, "converting public key hex to bytes"))
+		return nil, errors.Wrap(ErrInvalidHex, err.Error())
 	}
 
-	publicKey, err := ethcrypto.UnmarshalPubkey(publicKeyBytes)
+	// The constant "04" prefix (uncompressed point) is not part of the hexadecimal format, but go-ethereum expects it.
+	publicKey, err := ethcrypto.UnmarshalPubkey(append([]byte{0x04}, publicKeyBytes...))
 	if err != nil {
-		panic(errors.Wrap(err, "converting public key bytes to ecdsa"))
+		return nil, errors.Wrap(ErrOffCurve, err.Error())
 	}
 
+	return publicKey, nil
+}
+
+// MustPublicKeyHexToECDSA calls PublicKeyHexToECDSA and panics if it returns an error.
+func MustPublicKeyHexToECDSA(publicKeyHex string) *ecdsa.PublicKey {
+	publicKey, err := PublicKeyHexToECDSA(publicKeyHex)
+	if err != nil {
+		panic(errors.Wrap(err, "converting public key hex to ecdsa"))
+	}
 	return publicKey
 }
@@ -1,16 +1,14 @@
 package wallet
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
-)
+	"github.com/stretchr/testify/require"
 
-const (
-	// These are known to be valid according to MEW. Use them to test. Do NOT send funds to this address, it is not secure and was intentionally created to test with.
-	privateKeyHex = "7cd7d434407526ad4c7a64d4f7d26a2a45bb0da1cc7406c166e1e3ddfcce03ed"
-	publicKeyHex  = "ac7a41fcbb11cb057a1f0bf1710e7f0aab1c93a468c54f7472695b5b97c1af687b8a0692b1d37ff1d5e65d2ecd2f6befdf7d0c89a403a5bbafcd4a9143bb9de7"
-	address       = "0x19325d2D5c17AF1096D28A12850D27bD182612F6"
+	"github.com/Insulince/jeth/pkg/wallet/wallettest"
 )
 
 func Test_New(t *testing.T) {
@@ -18,55 +16,58 @@ func Test_New(t *testing.T) {
 }
 
 func Test_ManualHex(t *testing.T) {
-	w := ManualHex(privateKeyHex, publicKeyHex, address)
+	w, err := ManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, wallettest.Address)
+	require.NoError(t, err)
 
-	assert.Equal(t, privateKeyHex, w.PrivateKeyHex())
-	assert.Equal(t, publicKeyHex, w.PublicKeyHex())
-	assert.Equal(t, address, w.Address())
+	assert.Equal(t, wallettest.PrivateKeyHex, w.PrivateKeyHex())
+	assert.Equal(t, wallettest.PublicKeyHex, w.PublicKeyHex())
+	assert.Equal(t, wallettest.Address, w.Address())
 }
 
 func Test_Wallet_FromPrivateKeyHex(t *testing.T) {
-	w := FromPrivateKeyHex(privateKeyHex)
+	w, err := FromPrivateKeyHex(wallettest.PrivateKeyHex)
+	require.NoError(t, err)
 
-	assert.Equal(t, privateKeyHex, w.PrivateKeyHex())
-	assert.Equal(t, publicKeyHex, w.PublicKeyHex())
-	assert.Equal(t, address, w.Address())
+	assert.Equal(t, wallettest.PrivateKeyHex, w.PrivateKeyHex())
+	assert.Equal(t, wallettest.PublicKeyHex, w.PublicKeyHex())
+	assert.Equal(t, wallettest.Address, w.Address())
 }
 
 func Test_FromPublicKeyHex(t *testing.T) {
-	w := FromPublicKeyHex(privateKeyHex, publicKeyHex)
+	w, err := FromPublicKeyHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex)
+	require.NoError(t, err)
 
-	assert.Equal(t, privateKeyHex, w.PrivateKeyHex())
-	assert.Equal(t, publicKeyHex, w.PublicKeyHex())
-	assert.Equal(t, address, w.Address())
+	assert.Equal(t, wallettest.PrivateKeyHex, w.PrivateKeyHex())
+	assert.Equal(t, wallettest.PublicKeyHex, w.PublicKeyHex())
+	assert.Equal(t, wallettest.Address, w.Address())
 }
 
 func Test_Wallet_PrivateKeyHex(t *testing.T) {
-	w := ManualHex(privateKeyHex, publicKeyHex, address)
+	w := MustManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, wallettest.Address)
 
-	assert.Equal(t, privateKeyHex, w.PrivateKeyHex())
+	assert.Equal(t, wallettest.PrivateKeyHex, w.PrivateKeyHex())
 }
 
 func Test_Wallet_PublicKeyHex(t *testing.T) {
-	w := ManualHex(privateKeyHex, publicKeyHex, address)
+	w := MustManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, wallettest.Address)
 
-	assert.Equal(t, publicKeyHex, w.PublicKeyHex())
+	assert.Equal(t, wallettest.PublicKeyHex, w.PublicKeyHex())
 }
 
 func Test_Wallet_Address(t *testing.T) {
-	w := ManualHex(privateKeyHex, publicKeyHex, address)
+	w := MustManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, wallettest.Address)
 
-	assert.Equal(t, address, w.Address())
+	assert.Equal(t, wallettest.Address, w.Address())
 }
 
 func Test_Wallet_Clone(t *testing.T) {
-	w := ManualHex(privateKeyHex, publicKeyHex, address)
+	w := MustManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, wallettest.Address)
 	w2 := w.Clone()
 
 	// Assert w2 has the original values
-	assert.Equal(t, privateKeyHex, w2.PrivateKeyHex())
-	assert.Equal(t, publicKeyHex, w2.PublicKeyHex())
-	assert.Equal(t, address, w2.Address())
+	assert.Equal(t, wallettest.PrivateKeyHex, w2.PrivateKeyHex())
+	assert.Equal(t, wallettest.PublicKeyHex, w2.PublicKeyHex())
+	assert.Equal(t, wallettest.Address, w2.Address())
 
 	// Assert that w2 is equal to w
 	assert.Equal(t, w.PrivateKeyHex(), w2.PrivateKeyHex())
@@ -80,8 +81,8 @@ func Test_Wallet_Clone(t *testing.T) {
 }
 
 func Test_Wallet_Equals(t *testing.T) {
-	w := ManualHex(privateKeyHex, publicKeyHex, address)
-	w2 := ManualHex(privateKeyHex, publicKeyHex, address)
+	w := MustManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, wallettest.Address)
+	w2 := MustManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, wallettest.Address)
 
 	err := w.Equals(w2)
 	err2 := w2.Equals(w)
@@ -91,9 +92,136 @@ func Test_Wallet_Equals(t *testing.T) {
 }
 
 func Test_Wallet_Validate(t *testing.T) {
-	w := ManualHex(privateKeyHex, publicKeyHex, address)
+	w := MustManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, wallettest.Address)
 
 	err := w.Validate()
 
 	assert.NoError(t, err)
 }
+
+func Test_MustManualHex(t *testing.T) {
+	assert.NotPanics(t, func() { MustManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, wallettest.Address) })
+	assert.Panics(t, func() { MustManualHex(wallettest.PrivateKeyHex[1:], wallettest.PublicKeyHex, wallettest.Address) })
+}
+
+func Test_PrivateKeyHexToECDSA(t *testing.T) {
+	tests := []struct {
+		name          string
+		privateKeyHex string
+		err           error
+	}{
+		{name: "valid", privateKeyHex: wallettest.PrivateKeyHex, err: nil},
+		{name: "too short", privateKeyHex: wallettest.PrivateKeyHex[2:], err: ErrInvalidLength},
+		{name: "too long", privateKeyHex: wallettest.PrivateKeyHex + "00", err: ErrInvalidLength},
+		{name: "0x prefixed", privateKeyHex: "0x" + wallettest.PrivateKeyHex[2:], err: ErrInvalidHex},
+		{name: "not hex", privateKeyHex: "zz" + wallettest.PrivateKeyHex[2:], err: ErrInvalidHex},
+		{name: "zero", privateKeyHex: strings.Repeat("0", 64), err: ErrInvalidPrivateKey},
+		{name: "curve order", privateKeyHex: "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", err: ErrInvalidPrivateKey},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := PrivateKeyHexToECDSA(test.privateKeyHex)
+
+			if test.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, test.err), "expected %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func Test_PublicKeyHexToECDSA(t *testing.T) {
+	tests := []struct {
+		name         string
+		publicKeyHex string
+		err          error
+	}{
+		{name: "valid", publicKeyHex: wallettest.PublicKeyHex, err: nil},
+		{name: "too short", publicKeyHex: wallettest.PublicKeyHex[2:], err: ErrInvalidLength},
+		{name: "not hex", publicKeyHex: "zz" + wallettest.PublicKeyHex[2:], err: ErrInvalidHex},
+		{name: "off curve", publicKeyHex: wallettest.PublicKeyHex[:126] + "00", err: ErrOffCurve},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := PublicKeyHexToECDSA(test.publicKeyHex)
+
+			if test.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, test.err), "expected %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func Test_ManualHex_InvalidAddress(t *testing.T) {
+	_, err := ManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, wallettest.Address[2:])
+	assert.True(t, errors.Is(err, ErrInvalidLength))
+
+	_, err = ManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, "0x"+strings.Repeat("z", 40))
+	assert.True(t, errors.Is(err, ErrInvalidHex))
+}
+
+func Test_Wallet_Validate_AddressMismatch(t *testing.T) {
+	w := MustManualHex(wallettest.PrivateKeyHex, wallettest.PublicKeyHex, "0x0000000000000000000000000000000000000000")
+
+	err := w.Validate()
+
+	assert.True(t, errors.Is(err, ErrAddressMismatch))
+}
+
+func Test_WatchOnly(t *testing.T) {
+	w := WatchOnly(MustParseAddress(wallettest.Address))
+
+	assert.True(t, w.IsWatchOnly())
+	assert.False(t, w.HasPublicKey())
+	assert.Equal(t, "", w.PrivateKeyHex())
+	assert.Equal(t, "", w.PublicKeyHex())
+	assert.Equal(t, wallettest.Address, w.Address())
+	assert.NoError(t, w.Validate())
+	assert.NoError(t, w.Equals(w.Clone()))
+
+	_, err := w.SignMessage([]byte("jeth"))
+	assert.True(t, errors.Is(err, ErrWatchOnly))
+	_, err = w.EncryptKeystore("passphrase", StandardKeystoreOptions)
+	assert.True(t, errors.Is(err, ErrWatchOnly))
+}
+
+func Test_WatchOnlyFromPublicKeyHex(t *testing.T) {
+	w, err := WatchOnlyFromPublicKeyHex(wallettest.PublicKeyHex)
+	require.NoError(t, err)
+
+	assert.True(t, w.IsWatchOnly())
+	assert.True(t, w.HasPublicKey())
+	assert.Equal(t, "", w.PrivateKeyHex())
+	assert.Equal(t, wallettest.PublicKeyHex, w.PublicKeyHex())
+	assert.Equal(t, wallettest.Address, w.Address())
+	assert.NoError(t, w.Validate())
+	assert.NoError(t, w.Equals(w.Clone()))
+
+	// A watch-only wallet can still check signatures made by the full wallet.
+	signature, err := MustFromPrivateKeyHex(wallettest.PrivateKeyHex).SignMessage([]byte("jeth"))
+	require.NoError(t, err)
+	assert.NoError(t, w.VerifyMessage([]byte("jeth"), signature))
+
+	_, err = WatchOnlyFromPublicKeyHex(wallettest.PublicKeyHex[2:])
+	assert.True(t, errors.Is(err, ErrInvalidLength))
+}
+
+func Test_Wallet_Validate_WatchOnlyAddressMismatch(t *testing.T) {
+	w := MustWatchOnlyFromPublicKeyHex(wallettest.PublicKeyHex)
+	w.address = "0x0000000000000000000000000000000000000000"
+
+	err := w.Validate()
+
+	assert.True(t, errors.Is(err, ErrAddressMismatch))
+}
+
+func Test_Wallet_Addr(t *testing.T) {
+	w := MustFromPrivateKeyHex(wallettest.PrivateKeyHex)
+
+	assert.Equal(t, MustParseAddress(wallettest.Address), w.Addr())
+}
@@ -0,0 +1,9 @@
+// Package wallettest holds the wallet test fixtures shared by the tests of every package.
+package wallettest
+
+const (
+	// These are known to be valid according to MEW. Use them to test. Do NOT send funds to this address, it is not secure and was intentionally created to test with.
+	PrivateKeyHex = "7cd7d434407526ad4c7a64d4f7d26a2a45bb0da1cc7406c166e1e3ddfcce03ed"
+	PublicKeyHex  = "ac7a41fcbb11cb057a1f0bf1710e7f0aab1c93a468c54f7472695b5b97c1af687b8a0692b1d37ff1d5e65d2ecd2f6befdf7d0c89a403a5bbafcd4a9143bb9de7"
+	Address       = "0x19325d2D5c17AF1096D28A12850D27bD182612F6"
+)